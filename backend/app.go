@@ -2,22 +2,32 @@ package backend
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"mime"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
 type App struct {
-	ctx           context.Context
-	storage       *Storage
-	cache         *Cache
-	currentUser   *User
-	pomodoroTimer *PomodoroTimer
-	waterReminder *WaterReminder
-	driveService  *DriveService
+	ctx             context.Context
+	storage         *Storage
+	cache           *Cache
+	currentUser     *User
+	pomodoroTimer   *PomodoroTimer
+	waterReminder   *WaterReminder
+	overdueReminder *OverdueReminder
+	driveService    *DriveService
 }
 
 // NewApp creates a new App application struct
@@ -45,27 +55,34 @@ func (a *App) Startup(ctx context.Context) {
 	// Initialize cache
 	a.cache = NewCache()
 
-	// Initialize Pomodoro timer
+	// Initialize Pomodoro timer. The timer a user left running is restored when they log in.
 	a.pomodoroTimer = NewPomodoroTimer(a)
 
 	// Initialize water reminder
 	a.waterReminder = NewWaterReminder(a)
 
+	// Initialize overdue task reminder
+	a.overdueReminder = NewOverdueReminder(a)
+
 	// Initialize Drive service
 	a.driveService = NewDriveService(a.storage)
 }
 
 // shutdown is called when the app is closing
 func (a *App) Shutdown(_ context.Context) {
+	// Keep a running timer, so it is restored on the next start
+	if a.pomodoroTimer != nil {
+		a.pomodoroTimer.Close()
+	}
 	if a.storage != nil {
 		_ = a.storage.Close()
 	}
-	if a.pomodoroTimer != nil {
-		a.pomodoroTimer.Stop()
-	}
 	if a.waterReminder != nil {
 		a.waterReminder.Stop()
 	}
+	if a.overdueReminder != nil {
+		a.overdueReminder.Stop()
+	}
 }
 
 // ========== Authentication Methods ==========
@@ -137,18 +154,38 @@ func (a *App) Login(username, password string) (*User, error) {
 	// Cache user data
 	a.cache.SetWithExpiry(fmt.Sprintf("user:%d", user.ID), user, 24*time.Hour)
 
+	// Pick up the timer the user left
+	a.restoreTimer(user.ID)
+
 	// Load water reminder settings
 	settings, err := a.storage.GetWaterReminderSettings(user.ID)
 	if err == nil {
 		a.waterReminder.Start(user.ID, settings)
 	}
 
+	// Start checking for overdue tasks
+	a.overdueReminder.Start(user.ID)
+
+	// Empty the trash of tasks past their retention
+	if _, err := a.PurgeTrash(); err != nil {
+		log.Printf("failed to purge trash: %v", err)
+	}
+
 	// Return user without password hash, with token
 	user.PasswordHash = ""
 	user.Token = token
 	return user, nil
 }
 
+// restoreTimer gives the timer to a user logging in, with the phase they left running if
+// any. The timer of a user who did not log out is put aside first.
+func (a *App) restoreTimer(userID int64) {
+	a.pomodoroTimer.Release()
+	if err := a.pomodoroTimer.Restore(userID); err != nil {
+		log.Printf("failed to restore timer: %v", err)
+	}
+}
+
 // GetCurrentUser returns the current logged-in user
 func (a *App) GetCurrentUser() (*User, error) {
 	if a.currentUser == nil {
@@ -177,6 +214,16 @@ func (a *App) Logout(token string) error {
 		a.waterReminder.Stop()
 	}
 
+	// Stop overdue task reminder
+	if a.overdueReminder != nil {
+		a.overdueReminder.Stop()
+	}
+
+	// Put the user's timer aside until they log in again
+	if a.pomodoroTimer != nil {
+		a.pomodoroTimer.Release()
+	}
+
 	// Clear cache
 	a.cache.Delete(fmt.Sprintf("user:%d", a.currentUser.ID))
 
@@ -219,12 +266,23 @@ func (a *App) RestoreSession(token string) (*User, error) {
 	// Cache user data
 	a.cache.SetWithExpiry(fmt.Sprintf("user:%d", user.ID), user, 24*time.Hour)
 
+	// Pick up the timer the user left
+	a.restoreTimer(user.ID)
+
 	// Load water reminder settings
 	settings, err := a.storage.GetWaterReminderSettings(user.ID)
 	if err == nil && settings.Enabled {
 		a.waterReminder.Start(user.ID, settings)
 	}
 
+	// Start checking for overdue tasks
+	a.overdueReminder.Start(user.ID)
+
+	// Empty the trash of tasks past their retention
+	if _, err := a.PurgeTrash(); err != nil {
+		log.Printf("failed to purge trash: %v", err)
+	}
+
 	// Return user without password hash
 	userCopy := *user
 	userCopy.PasswordHash = ""
@@ -258,12 +316,16 @@ func (a *App) GetLanguage() string {
 
 // ========== Task Methods ==========
 
-// CreateTask creates a new task
-func (a *App) CreateTask(title, description string) (*Task, error) {
+// CreateTask creates a new task, optionally attached to a project
+func (a *App) CreateTask(title, description string, projectID *int64) (*Task, error) {
 	if a.currentUser == nil {
 		return nil, fmt.Errorf("no user logged in")
 	}
 
+	if err := a.checkProject(projectID, false); err != nil {
+		return nil, err
+	}
+
 	task := &Task{
 		ID:          GenerateID(),
 		UserID:      a.currentUser.ID,
@@ -271,6 +333,8 @@ func (a *App) CreateTask(title, description string) (*Task, error) {
 		Description: description,
 		Completed:   false,
 		CreatedAt:   time.Now(),
+		ProjectID:   projectID,
+		Occurrence:  1,
 	}
 
 	if err := a.storage.CreateTask(task); err != nil {
@@ -283,22 +347,32 @@ func (a *App) CreateTask(title, description string) (*Task, error) {
 	return task, nil
 }
 
-// GetTasks returns all tasks for the current user
-func (a *App) GetTasks() ([]Task, error) {
+// GetTasks returns the current user's tasks matching a filter
+func (a *App) GetTasks(filter TaskFilter) ([]Task, error) {
 	if a.currentUser == nil {
 		return nil, fmt.Errorf("no user logged in")
 	}
 
+	// Only the unfiltered list is cached
+	if !filter.isEmpty() {
+		tasks, err := a.storage.GetTasks(a.currentUser.ID, filter)
+		if err != nil {
+			return nil, err
+		}
+		return buildTaskTree(tasks), nil
+	}
+
 	// Check cache
 	cacheKey := fmt.Sprintf("tasks:%d", a.currentUser.ID)
 	if cached, ok := a.cache.Get(cacheKey); ok {
 		return cached.([]Task), nil
 	}
 
-	tasks, err := a.storage.GetTasks(a.currentUser.ID)
+	tasks, err := a.storage.GetTasks(a.currentUser.ID, filter)
 	if err != nil {
 		return nil, err
 	}
+	tasks = buildTaskTree(tasks)
 
 	// Cache tasks
 	a.cache.SetWithExpiry(cacheKey, tasks, 5*time.Minute)
@@ -307,25 +381,40 @@ func (a *App) GetTasks() ([]Task, error) {
 }
 
 // UpdateTask updates a task
-func (a *App) UpdateTask(taskID int64, title, description string, completed bool) error {
+func (a *App) UpdateTask(taskID int64, title, description string, completed bool, projectID *int64) error {
 	if a.currentUser == nil {
 		return fmt.Errorf("no user logged in")
 	}
 
+	existing, err := a.storage.GetTask(taskID, a.currentUser.ID)
+	if err != nil {
+		return err
+	}
+
+	// Tasks may stay in a project that was archived after they were attached
+	if err := a.checkProject(projectID, true); err != nil {
+		return err
+	}
+
 	task := &Task{
 		ID:          taskID,
 		UserID:      a.currentUser.ID,
 		Title:       title,
 		Description: description,
 		Completed:   completed,
+		ProjectID:   projectID,
 	}
 
+	// Keep the original completion time when a completed task is edited
+	if completed && existing.Completed {
+		task.CompletedAt = existing.CompletedAt
+	}
 	if completed && task.CompletedAt == nil {
 		now := time.Now()
 		task.CompletedAt = &now
 	}
 
-	if err := a.storage.UpdateTask(task); err != nil {
+	if err := a.storage.UpdateTaskAndRollUp(task, existing); err != nil {
 		return err
 	}
 
@@ -351,246 +440,1918 @@ func (a *App) DeleteTask(taskID int64) error {
 	return nil
 }
 
-// ========== Pomodoro Timer Methods ==========
+// defaultTrashRetentionDays is how long deleted tasks stay in the trash unless configured otherwise
+const defaultTrashRetentionDays = 30
 
-// StartPomodoro starts a Pomodoro timer
-func (a *App) StartPomodoro(durationMinutes int, taskID *int64) error {
+// GetTrash returns the tasks in the trash of the current user
+func (a *App) GetTrash() ([]Task, error) {
 	if a.currentUser == nil {
-		return fmt.Errorf("no user logged in")
+		return nil, fmt.Errorf("no user logged in")
 	}
 
-	return a.pomodoroTimer.Start(durationMinutes, taskID)
+	tasks, err := a.storage.GetDeletedTasks(a.currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	return tasks, nil
 }
 
-// PausePomodoro pauses the Pomodoro timer
-func (a *App) PausePomodoro() {
-	a.pomodoroTimer.Pause()
-}
+// RestoreTask takes a task and the subtasks deleted along with it out of the trash
+func (a *App) RestoreTask(taskID int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
 
-// ResumePomodoro resumes the Pomodoro timer
-func (a *App) ResumePomodoro() {
-	a.pomodoroTimer.Resume()
-}
+	if err := a.storage.RestoreTask(taskID, a.currentUser.ID); err != nil {
+		return err
+	}
 
-// StopPomodoro stops the Pomodoro timer
-func (a *App) StopPomodoro() {
-	a.pomodoroTimer.Stop()
-}
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
 
-// GetTimerState returns the current timer state
-func (a *App) GetTimerState() TimerState {
-	return a.pomodoroTimer.GetState()
+	return nil
 }
 
-// CompletePomodoro saves a completed Pomodoro session
-func (a *App) CompletePomodoro(durationMinutes int, taskID *int64) error {
+// PurgeTrash permanently removes the tasks that have been in the trash for longer than
+// the retention period, and returns how many were removed
+func (a *App) PurgeTrash() (int, error) {
 	if a.currentUser == nil {
-		return fmt.Errorf("no user logged in")
+		return 0, fmt.Errorf("no user logged in")
 	}
 
-	now := time.Now()
-	session := &PomodoroSession{
-		ID:          GenerateID(),
-		UserID:      a.currentUser.ID,
-		TaskID:      taskID,
-		Duration:    durationMinutes,
-		StartedAt:   now.Add(-time.Duration(durationMinutes) * time.Minute),
-		CompletedAt: now,
+	days, err := a.GetTrashRetention()
+	if err != nil {
+		return 0, err
+	}
+	purged, err := a.storage.PurgeTasks(a.currentUser.ID, time.Now().AddDate(0, 0, -days))
+	if err != nil || purged == 0 {
+		return purged, err
 	}
 
-	if err := a.storage.CreatePomodoroSession(session); err != nil {
-		return err
+	// Purged tasks may have left attached files behind
+	if _, err := a.CleanupAttachments(); err != nil {
+		return purged, err
 	}
+	return purged, nil
+}
 
-	// Invalidate sessions cache
-	a.cache.Delete(fmt.Sprintf("sessions:%d", a.currentUser.ID))
+// GetTrashRetention returns the number of days deleted tasks are kept in the trash
+func (a *App) GetTrashRetention() (int, error) {
+	if a.currentUser == nil {
+		return 0, fmt.Errorf("no user logged in")
+	}
 
-	return nil
+	value, err := a.storage.GetSetting(fmt.Sprintf("trash_retention_days:%d", a.currentUser.ID))
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return defaultTrashRetentionDays, nil
+	}
+	return strconv.Atoi(value)
 }
 
-// ========== Reporting Methods ==========
+// SetTrashRetention sets the number of days deleted tasks are kept in the trash
+func (a *App) SetTrashRetention(days int) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+	if days < 0 {
+		return fmt.Errorf("retention cannot be negative")
+	}
+
+	return a.storage.SaveSetting(fmt.Sprintf("trash_retention_days:%d", a.currentUser.ID), strconv.Itoa(days))
+}
 
-// GetSessions returns Pomodoro sessions within a date range
-func (a *App) GetSessions(startDate, endDate string) ([]PomodoroSession, error) {
+// CreateSubtask creates a task under a parent task. The subtask inherits the parent's project.
+func (a *App) CreateSubtask(parentID int64, title, description string) (*Task, error) {
 	if a.currentUser == nil {
 		return nil, fmt.Errorf("no user logged in")
 	}
 
-	start, err := time.Parse("2006-01-02", startDate)
+	parent, err := a.storage.GetTask(parentID, a.currentUser.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, err
+	task := &Task{
+		ID:          GenerateID(),
+		UserID:      a.currentUser.ID,
+		Title:       title,
+		Description: description,
+		Completed:   false,
+		CreatedAt:   time.Now(),
+		ProjectID:   parent.ProjectID,
+		ParentID:    &parent.ID,
+		Occurrence:  1,
 	}
-	// Set end to end of day
-	end = end.Add(24 * time.Hour).Add(-1 * time.Second)
 
-	sessions, err := a.storage.GetSessions(a.currentUser.ID, start, end)
-	if err != nil {
+	if err := a.storage.CreateTask(task); err != nil {
 		return nil, err
 	}
 
-	return sessions, nil
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return task, nil
 }
 
-// GetReport generates a report for the current user
-func (a *App) GetReport(startDate, endDate string) (map[string]interface{}, error) {
-	sessions, err := a.GetSessions(startDate, endDate)
-	if err != nil {
-		return nil, err
+// SetTaskParent moves a task under another task, or to the top level when parentID is nil
+func (a *App) SetTaskParent(taskID int64, parentID *int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
 	}
 
-	totalSessions := len(sessions)
-	totalMinutes := 0
-	taskCounts := make(map[int64]int)
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return err
+	}
 
-	for _, session := range sessions {
-		totalMinutes += session.Duration
-		if session.TaskID != nil {
-			taskCounts[*session.TaskID]++
+	// Walk up from the new parent to make sure the task does not end up under itself
+	for ancestorID := parentID; ancestorID != nil; {
+		if *ancestorID == taskID {
+			return fmt.Errorf("a task cannot be moved under its own subtask")
+		}
+		ancestor, err := a.storage.GetTask(*ancestorID, a.currentUser.ID)
+		if err != nil {
+			return err
 		}
+		ancestorID = ancestor.ParentID
 	}
 
-	report := map[string]interface{}{
-		"total_sessions": totalSessions,
-		"total_minutes":  totalMinutes,
-		"total_hours":    float64(totalMinutes) / 60.0,
-		"task_counts":    taskCounts,
+	if err := a.storage.SetTaskParent(taskID, a.currentUser.ID, parentID); err != nil {
+		return err
 	}
 
-	return report, nil
-}
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
 
-// ========== Water Reminder Methods ==========
+	return nil
+}
 
-// GetWaterReminderSettings returns water reminder settings
-func (a *App) GetWaterReminderSettings() (*WaterReminderSettings, error) {
+// SetTaskAutoComplete sets whether a task completes itself once all of its subtasks are completed
+func (a *App) SetTaskAutoComplete(taskID int64, autoComplete bool) error {
 	if a.currentUser == nil {
-		return nil, fmt.Errorf("no user logged in")
+		return fmt.Errorf("no user logged in")
 	}
 
-	return a.storage.GetWaterReminderSettings(a.currentUser.ID)
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return err
+	}
+
+	if err := a.storage.SetTaskAutoComplete(taskID, a.currentUser.ID, autoComplete); err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return nil
 }
 
-// SaveWaterReminderSettings saves water reminder settings
-func (a *App) SaveWaterReminderSettings(enabled bool, intervalMins int, customIntervalMins *int) error {
+// SetTaskEstimate sets the number of pomodoros a task is expected to take, 0 to clear it
+func (a *App) SetTaskEstimate(taskID int64, estimate int) error {
 	if a.currentUser == nil {
 		return fmt.Errorf("no user logged in")
 	}
 
-	// If custom interval is provided and valid, use it
-	actualInterval := intervalMins
-	if customIntervalMins != nil && *customIntervalMins > 0 {
-		actualInterval = *customIntervalMins
+	if estimate < 0 {
+		return fmt.Errorf("estimate cannot be negative")
 	}
 
-	settings := &WaterReminderSettings{
-		Enabled:            enabled,
-		IntervalMins:       actualInterval,
-		CustomIntervalMins: customIntervalMins,
-		LastReminder:       time.Now(),
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return err
 	}
 
-	if err := a.storage.SaveWaterReminderSettings(a.currentUser.ID, settings); err != nil {
-		log.Print(err.Error())
+	if err := a.storage.SetTaskEstimate(taskID, a.currentUser.ID, estimate); err != nil {
 		return err
 	}
 
-	// Update reminder
-	if enabled {
-		a.waterReminder.Start(a.currentUser.ID, settings)
-	} else {
-		a.waterReminder.Stop()
-	}
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
 
 	return nil
 }
 
-// ========== App Info Methods ==========
-
-// GetAppInfo returns application information
-func (a *App) GetAppInfo() map[string]interface{} {
-	return map[string]interface{}{
-		"name":        "Time Tracker",
-		"version":     "1.0.0",
-		"author":      "Pham Van Khang",
-		"email":       "phamvankhang.tvi@gmail.com",
-		"description": "Pomodoro timer and task tracker with water reminder",
-		"license":     "MIT",
-		"repository":  "https://github.com/vkhangstack/time-tracker-desktop",
+// SetTaskDueDate sets the due date (YYYY-MM-DD) of a task, or clears it when dueDate is empty
+func (a *App) SetTaskDueDate(taskID int64, dueDate string) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
 	}
-}
 
-// ========== Daily Retro Methods ==========
+	if dueDate != "" {
+		if _, err := time.Parse("2006-01-02", dueDate); err != nil {
+			return fmt.Errorf("invalid due date: %v", err)
+		}
+	}
 
-// DailySummary represents a summary of the day
-type DailySummary struct {
-	Date           string      `json:"date"`
-	CompletedTasks []Task      `json:"completed_tasks"`
-	TotalFocusTime int         `json:"total_focus_time"` // in minutes
-	Retro          *DailyRetro `json:"retro,omitempty"`
-}
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return err
+	}
 
-// GetDailyRetro returns the daily retro for a specific date
-func (a *App) GetUserDailyRetro(date string) (*DailyRetro, error) {
-	if a.currentUser == nil {
-		return nil, fmt.Errorf("no user logged in")
+	if err := a.storage.SetTaskDueDate(taskID, a.currentUser.ID, dueDate); err != nil {
+		return err
 	}
-	return a.storage.GetDailyRetro(a.currentUser.ID, date)
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return nil
 }
 
-// SaveDailyRetro saves the daily retro
-func (a *App) SaveDailyRetro(date, retroNotes, planNotes string) error {
+// SetTaskPriority sets the priority of a task, from 0 (none) to 3 (high)
+func (a *App) SetTaskPriority(taskID int64, priority int) error {
 	if a.currentUser == nil {
 		return fmt.Errorf("no user logged in")
 	}
 
-	// Check if exists first to preserve ID if updating
-	existing, _ := a.storage.GetDailyRetro(a.currentUser.ID, date)
+	if priority < TaskPriorityNone || priority > TaskPriorityHigh {
+		return fmt.Errorf("invalid priority: %d", priority)
+	}
 
-	now := time.Now()
-	retro := &DailyRetro{
-		UserID:     a.currentUser.ID,
-		Date:       date,
-		RetroNotes: retroNotes,
-		PlanNotes:  planNotes,
-		UpdatedAt:  now,
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return err
 	}
 
-	if existing != nil {
-		retro.ID = existing.ID
-		retro.CreatedAt = existing.CreatedAt
-	} else {
-		retro.ID = GenerateID()
-		retro.CreatedAt = now
+	if err := a.storage.SetTaskPriority(taskID, a.currentUser.ID, priority); err != nil {
+		return err
 	}
 
-	return a.storage.SaveDailyRetro(retro)
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return nil
 }
 
-// GetDailySummary returns a summary for the day including tasks, focus time, and retro
-func (a *App) GetDailySummary(date string) (*DailySummary, error) {
+// SetTaskRecurrence makes a task repeat according to an RRULE such as
+// "FREQ=WEEKLY;BYDAY=MO,TH", or stops it repeating when rule is empty
+func (a *App) SetTaskRecurrence(taskID int64, rule string) error {
 	if a.currentUser == nil {
-		return nil, fmt.Errorf("no user logged in")
+		return fmt.Errorf("no user logged in")
 	}
 
-	// Get completed tasks
-	tasks, err := a.storage.GetCompletedTasksForDate(a.currentUser.ID, date)
-	if err != nil {
-		return nil, err
+	if rule != "" {
+		if _, err := ParseRecurrenceRule(rule); err != nil {
+			return err
+		}
 	}
 
-	// Get focus time (sum of pomodoro sessions completed on this date)
-	// We need a helper for this or reuse GetSessions but with specific times
-	// For simplicity, let's use GetSessions with start/end of day
-	startTime, _ := time.Parse("2006-01-02", date)
-	endTime := startTime.Add(24 * time.Hour).Add(-1 * time.Second)
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return err
+	}
 
-	sessions, err := a.storage.GetSessions(a.currentUser.ID, startTime, endTime)
-	if err != nil {
+	if err := a.storage.SetTaskRecurrence(taskID, a.currentUser.ID, rule); err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return nil
+}
+
+// ReorderTasks persists a drag-and-drop order; taskIDs lists the tasks from top to bottom
+func (a *App) ReorderTasks(taskIDs []int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if err := a.storage.ReorderTasks(a.currentUser.ID, taskIDs); err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return nil
+}
+
+// AddTaskDependency marks a task as blocked by another task. Edges that would make a task
+// wait for itself, directly or through other tasks, are rejected.
+func (a *App) AddTaskDependency(taskID, blockedByID int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if taskID == blockedByID {
+		return fmt.Errorf("a task cannot be blocked by itself")
+	}
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return err
+	}
+	if _, err := a.storage.GetTask(blockedByID, a.currentUser.ID); err != nil {
+		return err
+	}
+
+	blockers, err := a.storage.getTaskBlockers(a.currentUser.ID)
+	if err != nil {
+		return err
+	}
+	if dependencyPathExists(blockers, blockedByID, taskID) {
+		return fmt.Errorf("dependency would create a cycle")
+	}
+
+	if err := a.storage.AddTaskDependency(taskID, blockedByID); err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return nil
+}
+
+// RemoveTaskDependency removes the edge marking a task as blocked by another task
+func (a *App) RemoveTaskDependency(taskID, blockedByID int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return err
+	}
+
+	if err := a.storage.RemoveTaskDependency(taskID, blockedByID); err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return nil
+}
+
+// GetTaskHistory returns the audit log of a task, oldest first. Tasks in the trash keep
+// their history.
+func (a *App) GetTaskHistory(taskID int64) ([]TaskEvent, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+	return a.storage.GetTaskEvents(taskID, a.currentUser.ID)
+}
+
+// GetActivity returns every task event of the current user on a day (YYYY-MM-DD), oldest first
+func (a *App) GetActivity(date string) ([]TaskEvent, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	start, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	return a.storage.GetTaskEventsBetween(a.currentUser.ID, start, start.AddDate(0, 0, 1))
+}
+
+// ========== Bulk Task Methods ==========
+
+// BulkCompleteTasks completes many tasks in a single transaction
+func (a *App) BulkCompleteTasks(taskIDs []int64) ([]BulkResult, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+	return a.runTaskBatch(func() ([]BulkResult, error) {
+		return a.storage.SetTasksCompleted(a.currentUser.ID, taskIDs, true)
+	})
+}
+
+// BulkReopenTasks reopens many tasks in a single transaction
+func (a *App) BulkReopenTasks(taskIDs []int64) ([]BulkResult, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+	return a.runTaskBatch(func() ([]BulkResult, error) {
+		return a.storage.SetTasksCompleted(a.currentUser.ID, taskIDs, false)
+	})
+}
+
+// BulkDeleteTasks moves many tasks, with their subtasks, to the trash in a single transaction
+func (a *App) BulkDeleteTasks(taskIDs []int64) ([]BulkResult, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+	return a.runTaskBatch(func() ([]BulkResult, error) {
+		return a.storage.DeleteTasks(a.currentUser.ID, taskIDs)
+	})
+}
+
+// BulkRetagTasks adds and removes tags on many tasks in a single transaction. Missing tags
+// to add are created in the same transaction.
+func (a *App) BulkRetagTasks(taskIDs []int64, addTags, removeTags []string) ([]BulkResult, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+	return a.runTaskBatch(func() ([]BulkResult, error) {
+		return a.storage.RetagTasks(a.currentUser.ID, taskIDs, normalizeTagNames(addTags), normalizeTagNames(removeTags))
+	})
+}
+
+// BulkMoveTasks moves many tasks to a workflow state in a single transaction
+func (a *App) BulkMoveTasks(taskIDs []int64, state string) ([]BulkResult, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	states, err := a.storage.GetWorkflowStates(a.currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	target := findWorkflowState(states, state)
+	if target == nil {
+		return nil, fmt.Errorf("unknown workflow state: %s", state)
+	}
+
+	return a.runTaskBatch(func() ([]BulkResult, error) {
+		return a.storage.MoveTasks(a.currentUser.ID, taskIDs, *target)
+	})
+}
+
+// runTaskBatch runs a bulk operation, which rolls completion changes and deletions up to
+// the parent tasks and schedules recurring occurrences in its own transaction, as the
+// single-task bindings do. The task cache is invalidated once for the whole batch.
+func (a *App) runTaskBatch(batch func() ([]BulkResult, error)) ([]BulkResult, error) {
+	results, err := batch()
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return results, nil
+}
+
+// ========== Workflow Methods ==========
+
+// GetWorkflowStates returns the workflow states of the current user in board order
+func (a *App) GetWorkflowStates() ([]WorkflowState, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+	return a.storage.GetWorkflowStates(a.currentUser.ID)
+}
+
+// SetWorkflowStates replaces the workflow of the current user. States keep their ID when
+// renamed; tasks in a removed state go back to the first open state, or to the terminal one
+// when completed.
+func (a *App) SetWorkflowStates(states []WorkflowState) ([]WorkflowState, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	if err := validateWorkflowStates(states); err != nil {
+		return nil, err
+	}
+	if err := a.storage.SaveWorkflowStates(a.currentUser.ID, states); err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return a.storage.GetWorkflowStates(a.currentUser.ID)
+}
+
+// MoveTask moves a task to another workflow state. Moving into the terminal state completes
+// the task and moving out of it reopens the task, just like UpdateTask.
+func (a *App) MoveTask(taskID int64, state string) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	existing, err := a.storage.GetTask(taskID, a.currentUser.ID)
+	if err != nil {
+		return err
+	}
+
+	states, err := a.storage.GetWorkflowStates(a.currentUser.ID)
+	if err != nil {
+		return err
+	}
+	target := findWorkflowState(states, state)
+	if target == nil {
+		return fmt.Errorf("unknown workflow state: %s", state)
+	}
+	if target.Name == existing.State {
+		return nil
+	}
+
+	task := *existing
+	task.State = target.Name
+	task.Completed = target.Terminal
+	task.CompletedAt = nil
+	if task.Completed {
+		task.CompletedAt = existing.CompletedAt
+		if task.CompletedAt == nil {
+			now := time.Now()
+			task.CompletedAt = &now
+		}
+	}
+
+	if err := a.storage.UpdateTaskAndRollUp(&task, existing); err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return nil
+}
+
+// GetBoard returns the tasks of the current user matching a filter, grouped into one column
+// per workflow state. Subtasks appear on the board next to their parents.
+func (a *App) GetBoard(filter TaskFilter) ([]BoardColumn, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	states, err := a.storage.GetWorkflowStates(a.currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := a.storage.GetTasks(a.currentUser.ID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]BoardColumn, len(states))
+	byState := make(map[string]*BoardColumn, len(states))
+	for i, state := range states {
+		columns[i] = BoardColumn{State: state, Tasks: []Task{}}
+		byState[state.Name] = &columns[i]
+	}
+
+	for _, task := range tasks {
+		column, ok := byState[task.State]
+		if !ok {
+			column = byState[workflowStateFor(states, task.Completed)]
+		}
+		column.Tasks = append(column.Tasks, task)
+	}
+
+	return columns, nil
+}
+
+// GetTaskTransitions returns every workflow state change of a task, oldest first
+func (a *App) GetTaskTransitions(taskID int64) ([]TaskTransition, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+	return a.storage.GetTaskTransitions(taskID, a.currentUser.ID)
+}
+
+// ========== Template Methods ==========
+
+// CreateTemplate saves a new task template for the current user
+func (a *App) CreateTemplate(template TaskTemplate) (*TaskTemplate, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	if err := normalizeTemplate(&template); err != nil {
+		return nil, err
+	}
+	template.ID = GenerateID()
+	template.UserID = a.currentUser.ID
+	template.CreatedAt = time.Now()
+
+	if err := a.storage.SaveTemplate(&template); err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// GetTemplates returns the current user's task templates, by name
+func (a *App) GetTemplates() ([]TaskTemplate, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	return a.storage.GetTemplates(a.currentUser.ID)
+}
+
+// UpdateTemplate replaces the fields of an existing task template
+func (a *App) UpdateTemplate(template TaskTemplate) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	existing, err := a.storage.GetTemplate(template.ID, a.currentUser.ID)
+	if err != nil {
+		return err
+	}
+	if err := normalizeTemplate(&template); err != nil {
+		return err
+	}
+	template.UserID = existing.UserID
+	template.CreatedAt = existing.CreatedAt
+
+	return a.storage.SaveTemplate(&template)
+}
+
+// DeleteTemplate deletes a task template of the current user
+func (a *App) DeleteTemplate(templateID int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetTemplate(templateID, a.currentUser.ID); err != nil {
+		return err
+	}
+
+	return a.storage.DeleteTemplate(templateID, a.currentUser.ID)
+}
+
+// InstantiateTemplate creates a task from a template for each date (YYYY-MM-DD), or for
+// today when no date is given. Placeholders such as {date} and {week} are expanded for
+// the date, and checklist items become subtasks of the new task. Either all of the tasks
+// are created or none of them.
+func (a *App) InstantiateTemplate(templateID int64, dates []string) ([]Task, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	template, err := a.storage.GetTemplate(templateID, a.currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dates) == 0 {
+		dates = []string{time.Now().Format("2006-01-02")}
+	}
+	days := make([]time.Time, len(dates))
+	for i, date := range dates {
+		days[i], err = time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+		}
+	}
+
+	// Build every task first, so that all of them are created in one transaction
+	var tasks []*Task
+	tags := make(map[int64][]string, len(days))
+	parents := make([]*Task, 0, len(days))
+	subtasks := make(map[int64][]*Task, len(days))
+	for _, day := range days {
+		task := &Task{
+			ID:                 GenerateID(),
+			UserID:             a.currentUser.ID,
+			Title:              expandTemplate(template.TitlePattern, day),
+			Description:        expandTemplate(template.Description, day),
+			CreatedAt:          time.Now(),
+			AutoComplete:       len(template.Checklist) > 0,
+			EstimatedPomodoros: template.EstimatedPomodoros,
+			Occurrence:         1,
+		}
+		tasks = append(tasks, task)
+		tags[task.ID] = normalizeTagNames(template.Tags)
+		parents = append(parents, task)
+
+		for _, item := range template.Checklist {
+			subtask := &Task{
+				ID:         GenerateID(),
+				UserID:     a.currentUser.ID,
+				Title:      expandTemplate(item, day),
+				CreatedAt:  time.Now(),
+				ParentID:   &task.ID,
+				Occurrence: 1,
+			}
+			tasks = append(tasks, subtask)
+			subtasks[task.ID] = append(subtasks[task.ID], subtask)
+		}
+	}
+
+	if err := a.storage.CreateTasks(tasks, tags); err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	created := make([]Task, 0, len(parents))
+	for _, parent := range parents {
+		for _, subtask := range subtasks[parent.ID] {
+			parent.Children = append(parent.Children, *subtask)
+		}
+		if len(parent.Children) > 0 {
+			parent.Progress = &TaskProgress{Total: len(parent.Children)}
+		}
+		created = append(created, *parent)
+	}
+
+	return created, nil
+}
+
+// ========== Import Methods ==========
+
+// PreviewImport reads an import file and lists the tasks ImportTasks would create and the
+// ones it would skip as duplicates, without creating anything
+func (a *App) PreviewImport(format, data string) (*ImportPreview, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	preview, _, _, err := a.prepareImport(format, data)
+	return preview, err
+}
+
+// ImportTasks creates the tasks of an import file in one transaction. Format is one of
+// the ImportFormat constants. Tasks that already exist under the same parent are skipped,
+// so importing the same file twice creates nothing the second time.
+func (a *App) ImportTasks(format, data string) (*ImportPreview, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	preview, tasks, tags, err := a.prepareImport(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return preview, nil
+	}
+
+	if err := a.storage.CreateTasks(tasks, tags); err != nil {
+		return nil, err
+	}
+	preview.DryRun = false
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return preview, nil
+}
+
+// prepareImport parses an import file and builds the tasks to create, with the tag names of
+// each task by task ID
+func (a *App) prepareImport(format, data string) (*ImportPreview, []*Task, map[int64][]string, error) {
+	imported, err := parseImport(format, data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	existing, err := a.storage.GetTasks(a.currentUser.ID, TaskFilter{})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	projects := make(map[int64]*int64, len(existing))
+	for _, task := range existing {
+		projects[task.ID] = task.ProjectID
+	}
+
+	targets := markImportDuplicates(imported, existing)
+	preview := &ImportPreview{
+		Format: format,
+		Tasks:  imported,
+		DryRun: true,
+	}
+
+	var tasks []*Task
+	tags := make(map[int64][]string)
+	ids := make([]int64, len(imported))
+	now := time.Now()
+	for i, item := range imported {
+		if item.Duplicate {
+			preview.Skipped++
+			continue
+		}
+		preview.Created++
+
+		task := &Task{
+			ID:          GenerateID(),
+			UserID:      a.currentUser.ID,
+			Title:       item.Title,
+			Description: item.Description,
+			Completed:   item.Completed,
+			CreatedAt:   now,
+			DueDate:     item.DueDate,
+			Priority:    item.Priority,
+			Occurrence:  1,
+		}
+		if item.Completed {
+			task.CompletedAt = &now
+		}
+		if item.Parent >= 0 {
+			parentID := targets[item.Parent]
+			if parentID < 0 {
+				// The parent is created by this import as well
+				parentID = ids[-parentID-1]
+			}
+			task.ParentID = &parentID
+			task.ProjectID = projects[parentID]
+		}
+		ids[i] = task.ID
+		tags[task.ID] = item.Tags
+		tasks = append(tasks, task)
+	}
+
+	return preview, tasks, tags, nil
+}
+
+// ========== Attachment Methods ==========
+
+// AttachFiles lets the user pick files and attaches them to a task
+func (a *App) AttachFiles(taskID int64) ([]Attachment, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return nil, err
+	}
+
+	paths, err := wailsruntime.OpenMultipleFilesDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title: "Attach files",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := []Attachment{}
+	for _, path := range paths {
+		attachment, err := a.AddAttachment(taskID, path)
+		if err != nil {
+			return attachments, err
+		}
+		attachments = append(attachments, *attachment)
+	}
+	return attachments, nil
+}
+
+// AddAttachment copies a file into the attachment folder and attaches it to a task
+func (a *App) AddAttachment(taskID int64, path string) (*Attachment, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("cannot attach a folder")
+	}
+
+	dir, err := GetAttachmentsDir()
+	if err != nil {
+		return nil, err
+	}
+	hash, size, err := storeAttachment(dir, file)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(path)
+	mimeType := mime.TypeByExtension(filepath.Ext(name))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	attachment := &Attachment{
+		ID:        GenerateID(),
+		UserID:    a.currentUser.ID,
+		TaskID:    taskID,
+		Name:      name,
+		Hash:      hash,
+		Size:      size,
+		MimeType:  mimeType,
+		CreatedAt: time.Now(),
+	}
+	if err := a.storage.CreateAttachment(attachment); err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// GetAttachments returns the files attached to a task
+func (a *App) GetAttachments(taskID int64) ([]Attachment, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	return a.storage.GetAttachments(taskID, a.currentUser.ID)
+}
+
+// OpenAttachment opens an attachment with the default application of the system. A copy
+// under its original name is opened, so the stored file keeps matching its hash.
+func (a *App) OpenAttachment(attachmentID int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	attachment, err := a.storage.GetAttachment(attachmentID, a.currentUser.ID)
+	if err != nil {
+		return err
+	}
+
+	dir, err := GetAttachmentsDir()
+	if err != nil {
+		return err
+	}
+	path, err := attachmentPath(dir, attachment.Hash)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read attachment: %v", err)
+	}
+
+	copyDir := filepath.Join(os.TempDir(), "time-tracker-attachments", strconv.FormatInt(attachment.ID, 10))
+	if err := os.MkdirAll(copyDir, 0700); err != nil {
+		return err
+	}
+	// The copy is readable by the current user only, even when left over from an earlier open
+	copyPath := filepath.Join(copyDir, filepath.Base(attachment.Name))
+	if err := os.WriteFile(copyPath, content, 0600); err != nil {
+		return err
+	}
+	if err := os.Chmod(copyPath, 0600); err != nil {
+		return err
+	}
+
+	wailsruntime.BrowserOpenURL(a.ctx, fileURL(copyPath))
+	return nil
+}
+
+// RemoveAttachment detaches a file from its task, deleting the stored file when no other
+// attachment uses it
+func (a *App) RemoveAttachment(attachmentID int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetAttachment(attachmentID, a.currentUser.ID); err != nil {
+		return err
+	}
+	if err := a.storage.DeleteAttachment(attachmentID, a.currentUser.ID); err != nil {
+		return err
+	}
+
+	_, err := a.CleanupAttachments()
+	return err
+}
+
+// CleanupAttachments deletes stored files that no attachment refers to anymore and
+// returns how many were deleted
+func (a *App) CleanupAttachments() (int, error) {
+	if a.currentUser == nil {
+		return 0, fmt.Errorf("no user logged in")
+	}
+
+	hashes, err := a.storage.GetAttachmentHashes()
+	if err != nil {
+		return 0, err
+	}
+	dir, err := GetAttachmentsDir()
+	if err != nil {
+		return 0, err
+	}
+	return removeOrphanedAttachments(dir, hashes)
+}
+
+// GetBackupAttachments reports whether backups include the content of attached files
+func (a *App) GetBackupAttachments() (bool, error) {
+	if a.currentUser == nil {
+		return false, fmt.Errorf("no user logged in")
+	}
+
+	value, err := a.storage.GetSetting("backup_attachments")
+	if err != nil {
+		return false, err
+	}
+	return value == "true", nil
+}
+
+// SetBackupAttachments sets whether backups include the content of attached files
+func (a *App) SetBackupAttachments(include bool) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	return a.storage.SaveSetting("backup_attachments", strconv.FormatBool(include))
+}
+
+// ========== Project Methods ==========
+
+// CreateProject creates a new project
+func (a *App) CreateProject(name, color string) (*Project, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("project name is required")
+	}
+
+	project := &Project{
+		ID:        GenerateID(),
+		UserID:    a.currentUser.ID,
+		Name:      name,
+		Color:     color,
+		CreatedAt: time.Now(),
+	}
+
+	if err := a.storage.CreateProject(project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// GetProjects returns the current user's projects
+func (a *App) GetProjects(includeArchived bool) ([]Project, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	return a.storage.GetProjects(a.currentUser.ID, includeArchived)
+}
+
+// UpdateProject renames or recolors a project
+func (a *App) UpdateProject(projectID int64, name, color string) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if name == "" {
+		return fmt.Errorf("project name is required")
+	}
+
+	return a.storage.UpdateProject(&Project{
+		ID:     projectID,
+		UserID: a.currentUser.ID,
+		Name:   name,
+		Color:  color,
+	})
+}
+
+// ArchiveProject archives a project so it is hidden from new tasks
+func (a *App) ArchiveProject(projectID int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetProject(projectID, a.currentUser.ID); err != nil {
+		return err
+	}
+
+	return a.storage.ArchiveProject(projectID, a.currentUser.ID)
+}
+
+// checkProject verifies that an optional project belongs to the current user
+func (a *App) checkProject(projectID *int64, allowArchived bool) error {
+	if projectID == nil {
+		return nil
+	}
+
+	project, err := a.storage.GetProject(*projectID, a.currentUser.ID)
+	if err != nil {
+		return err
+	}
+	if project.Archived && !allowArchived {
+		return fmt.Errorf("project is archived")
+	}
+
+	return nil
+}
+
+// ========== Tag Methods ==========
+
+// GetTags returns all tags of the current user
+func (a *App) GetTags() ([]Tag, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	return a.storage.GetTags(a.currentUser.ID)
+}
+
+// AddTaskTag puts a tag on a task, creating the tag if it does not exist yet
+func (a *App) AddTaskTag(taskID int64, name string) (*Tag, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return nil, err
+	}
+
+	tag, err := a.getOrCreateTag(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.storage.AddTaskTag(taskID, tag.ID); err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return tag, nil
+}
+
+// RemoveTaskTag removes a tag from a task
+func (a *App) RemoveTaskTag(taskID, tagID int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetTask(taskID, a.currentUser.ID); err != nil {
+		return err
+	}
+
+	if err := a.storage.RemoveTaskTag(taskID, tagID); err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	a.cache.Delete(fmt.Sprintf("tasks:%d", a.currentUser.ID))
+
+	return nil
+}
+
+// AddSessionTag puts a tag on a Pomodoro session, creating the tag if it does not exist yet
+func (a *App) AddSessionTag(sessionID int64, name string) (*Tag, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetPomodoroSession(sessionID, a.currentUser.ID); err != nil {
+		return nil, err
+	}
+
+	tag, err := a.getOrCreateTag(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.storage.AddSessionTag(sessionID, tag.ID); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// RemoveSessionTag removes a tag from a Pomodoro session
+func (a *App) RemoveSessionTag(sessionID, tagID int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetPomodoroSession(sessionID, a.currentUser.ID); err != nil {
+		return err
+	}
+
+	return a.storage.RemoveSessionTag(sessionID, tagID)
+}
+
+// getOrCreateTag looks up a tag of the current user by name, creating it if needed
+func (a *App) getOrCreateTag(name string) (*Tag, error) {
+	name = normalizeTagName(name)
+	if name == "" {
+		return nil, fmt.Errorf("tag name is required")
+	}
+
+	return a.storage.GetOrCreateTag(a.currentUser.ID, name)
+}
+
+// ========== Pomodoro Timer Methods ==========
+
+// StartPomodoro starts the upcoming phase of the Pomodoro cycle, see TimerState.Phase.
+// durationMinutes overrides the configured length of a work phase when positive. Working on
+// a blocked task is allowed, so it returns the tasks still blocking the chosen one for the
+// caller to warn about.
+func (a *App) StartPomodoro(durationMinutes int, taskID *int64) ([]Task, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	var blockers []Task
+	if taskID != nil {
+		var err error
+		if blockers, err = a.storage.GetOpenBlockers(*taskID, a.currentUser.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	settings, err := a.GetPomodoroSettings()
+	if err != nil {
+		return nil, err
+	}
+	a.pomodoroTimer.SetSettings(*settings)
+
+	if err := a.pomodoroTimer.Start(a.currentUser.ID, durationMinutes, taskID); err != nil {
+		return nil, err
+	}
+	if blockers == nil {
+		blockers = []Task{}
+	}
+	return blockers, nil
+}
+
+// StartStopwatch starts counting up on a task until the timer is stopped, for work that
+// does not fit a fixed pomodoro. Like StartPomodoro, it returns the tasks still blocking
+// the chosen one.
+func (a *App) StartStopwatch(taskID *int64) ([]Task, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	var blockers []Task
+	if taskID != nil {
+		var err error
+		if blockers, err = a.storage.GetOpenBlockers(*taskID, a.currentUser.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.pomodoroTimer.StartStopwatch(a.currentUser.ID, taskID); err != nil {
+		return nil, err
+	}
+	if blockers == nil {
+		blockers = []Task{}
+	}
+	return blockers, nil
+}
+
+// ExtendPomodoro adds minutes to the running phase of the Pomodoro cycle
+func (a *App) ExtendPomodoro(minutes int) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if minutes < 1 || minutes > 60 {
+		return fmt.Errorf("extension must be between 1 and 60 minutes")
+	}
+	return a.pomodoroTimer.ExtendPhase(minutes)
+}
+
+// SkipPhase ends the current phase of the Pomodoro cycle early and moves on to the next one
+func (a *App) SkipPhase() error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	return a.pomodoroTimer.SkipPhase()
+}
+
+// SwitchTask moves the running pomodoro or stopwatch on to another task without stopping
+// it. The time before and after the switch is recorded against each task. Like
+// StartPomodoro, it returns the tasks still blocking the new one.
+func (a *App) SwitchTask(taskID *int64) ([]Task, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	var blockers []Task
+	if taskID != nil {
+		var err error
+		if blockers, err = a.storage.GetOpenBlockers(*taskID, a.currentUser.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.pomodoroTimer.SwitchTask(taskID); err != nil {
+		return nil, err
+	}
+	if blockers == nil {
+		blockers = []Task{}
+	}
+	return blockers, nil
+}
+
+// ResolveIdle settles the time the timer was paused while the user was away, once they are
+// back: keep it as focus time, discard it, or reassign it to the task with taskID
+func (a *App) ResolveIdle(action string, taskID *int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if action == IdleActionReassign && taskID != nil {
+		if _, err := a.storage.GetTask(*taskID, a.currentUser.ID); err != nil {
+			return err
+		}
+	}
+	return a.pomodoroTimer.ResolveIdle(action, taskID)
+}
+
+// PausePomodoro pauses the Pomodoro timer
+func (a *App) PausePomodoro() {
+	a.pomodoroTimer.Pause()
+}
+
+// ResumePomodoro resumes the Pomodoro timer
+func (a *App) ResumePomodoro() {
+	a.pomodoroTimer.Resume()
+}
+
+// LogInterruption notes an internal or external interruption of the running pomodoro
+func (a *App) LogInterruption(kind, note string) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if err := validateInterruptionKind(kind); err != nil {
+		return err
+	}
+	return a.pomodoroTimer.LogInterruption(kind, strings.TrimSpace(note))
+}
+
+// StopPomodoro stops the Pomodoro timer. A work phase stopped early is recorded as an
+// abandoned session with the given reason, which may be empty.
+func (a *App) StopPomodoro(reason string) {
+	a.pomodoroTimer.Stop(strings.TrimSpace(reason))
+}
+
+// GetTimerState returns the current timer state
+func (a *App) GetTimerState() TimerState {
+	return a.pomodoroTimer.GetState()
+}
+
+// ResetPomodoroCycle stops the timer and starts the cycle over with a work phase
+func (a *App) ResetPomodoroCycle() {
+	a.pomodoroTimer.ResetCycle()
+}
+
+// GetPomodoroSettings returns the current user's Pomodoro cycle settings
+func (a *App) GetPomodoroSettings() (*PomodoroSettings, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	return a.pomodoroSettings(a.currentUser.ID)
+}
+
+// pomodoroSettings loads the Pomodoro cycle settings of a user, falling back to the defaults
+func (a *App) pomodoroSettings(userID int64) (*PomodoroSettings, error) {
+	settings := defaultPomodoroSettings
+	value, err := a.storage.GetSetting(fmt.Sprintf("pomodoro_settings:%d", userID))
+	if err != nil {
+		return nil, err
+	}
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse pomodoro settings: %v", err)
+		}
+	}
+	return &settings, nil
+}
+
+// SavePomodoroSettings saves the current user's Pomodoro cycle settings. A running phase
+// keeps its length; the new settings apply from the next phase on.
+func (a *App) SavePomodoroSettings(settings PomodoroSettings) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if err := settings.validate(); err != nil {
+		return err
+	}
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	if err := a.storage.SaveSetting(fmt.Sprintf("pomodoro_settings:%d", a.currentUser.ID), string(value)); err != nil {
+		return err
+	}
+
+	a.pomodoroTimer.SetSettings(settings)
+	return nil
+}
+
+// CompletePomodoro saves a Pomodoro session entered by hand that ended just now, lasting
+// at most as long as a phase can. Like AddSession, it must not overlap any recorded
+// session. Sessions timed with StartPomodoro are recorded by the timer itself.
+func (a *App) CompletePomodoro(durationMinutes int, taskID *int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if durationMinutes < 1 || durationMinutes > maxPhaseMins {
+		return fmt.Errorf("duration must be between 1 and %d minutes", maxPhaseMins)
+	}
+	if taskID != nil {
+		if _, err := a.storage.GetTask(*taskID, a.currentUser.ID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	session := &PomodoroSession{
+		ID:          GenerateID(),
+		UserID:      a.currentUser.ID,
+		TaskID:      taskID,
+		Duration:    durationMinutes,
+		StartedAt:   now.Add(-time.Duration(durationMinutes) * time.Minute),
+		CompletedAt: now,
+	}
+	if err := validateSessionRange(session.StartedAt, session.CompletedAt); err != nil {
+		return err
+	}
+	if err := a.checkSessionOverlap(session.StartedAt, session.CompletedAt); err != nil {
+		return err
+	}
+
+	if err := a.storage.CreatePomodoroSession(session); err != nil {
+		return err
+	}
+
+	// Invalidate sessions cache
+	a.cache.Delete(fmt.Sprintf("sessions:%d", a.currentUser.ID))
+
+	return nil
+}
+
+// SetSessionNotes sets the notes of a recorded Pomodoro session
+func (a *App) SetSessionNotes(sessionID int64, notes string) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetPomodoroSession(sessionID, a.currentUser.ID); err != nil {
+		return err
+	}
+
+	return a.storage.SetSessionNotes(sessionID, a.currentUser.ID, notes)
+}
+
+// AddSession records time tracked without the timer, from startedAt to completedAt given
+// as RFC 3339 timestamps. It must not overlap any recorded session.
+func (a *App) AddSession(taskID *int64, startedAt, completedAt, notes string) (*PomodoroSession, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	session := &PomodoroSession{
+		ID:     GenerateID(),
+		UserID: a.currentUser.ID,
+		Notes:  strings.TrimSpace(notes),
+		Mode:   TimerModeStopwatch,
+	}
+	if err := a.editSession(session, taskID, startedAt, completedAt); err != nil {
+		return nil, err
+	}
+	if err := a.storage.CreatePomodoroSession(session); err != nil {
+		return nil, err
+	}
+
+	a.cache.Delete(fmt.Sprintf("sessions:%d", a.currentUser.ID))
+	return a.getSession(session.ID)
+}
+
+// UpdateSession changes the task, times and notes of a recorded session. Pauses and
+// interruptions outside of the new times are dropped.
+func (a *App) UpdateSession(sessionID int64, taskID *int64, startedAt, completedAt, notes string) (*PomodoroSession, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	session, err := a.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	session.Notes = strings.TrimSpace(notes)
+	if err := a.editSession(session, taskID, startedAt, completedAt); err != nil {
+		return nil, err
+	}
+	if err := a.storage.UpdatePomodoroSession(session); err != nil {
+		return nil, err
+	}
+
+	a.cache.Delete(fmt.Sprintf("sessions:%d", a.currentUser.ID))
+	return a.getSession(session.ID)
+}
+
+// editSession sets the task and times of a new or edited session, checking that the task
+// exists and that the times do not overlap another session
+func (a *App) editSession(session *PomodoroSession, taskID *int64, startedAt, completedAt string) error {
+	start, err := parseSessionTime(startedAt)
+	if err != nil {
+		return err
+	}
+	end, err := parseSessionTime(completedAt)
+	if err != nil {
+		return err
+	}
+	if err := validateSessionRange(start, end); err != nil {
+		return err
+	}
+	if taskID != nil {
+		if _, err := a.storage.GetTask(*taskID, a.currentUser.ID); err != nil {
+			return err
+		}
+	}
+	if err := a.checkSessionOverlap(start, end, session.ID); err != nil {
+		return err
+	}
+
+	session.TaskID = taskID
+	session.StartedAt = start
+	session.CompletedAt = end
+	return fitSession(session)
+}
+
+// SplitSession cuts a recorded session in two at a time within it, given as an RFC 3339
+// timestamp. The second part gets the same task and tags.
+func (a *App) SplitSession(sessionID int64, at string) ([]PomodoroSession, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	session, err := a.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	cut, err := parseSessionTime(at)
+	if err != nil {
+		return nil, err
+	}
+	first, second, err := splitSession(*session, cut)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.storage.SplitPomodoroSession(first, second); err != nil {
+		return nil, err
+	}
+
+	a.cache.Delete(fmt.Sprintf("sessions:%d", a.currentUser.ID))
+	result := make([]PomodoroSession, 0, 2)
+	for _, id := range []int64{first.ID, second.ID} {
+		part, err := a.getSession(id)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *part)
+	}
+	return result, nil
+}
+
+// MergeSessions joins two recorded sessions with no other session between them into one.
+// The earlier session keeps its ID and task, and the time between them counts as paused.
+func (a *App) MergeSessions(sessionID, otherID int64) (*PomodoroSession, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	if sessionID == otherID {
+		return nil, fmt.Errorf("cannot merge a session with itself")
+	}
+	session, err := a.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	other, err := a.getSession(otherID)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergeSessions(*session, *other)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkSessionOverlap(merged.StartedAt, merged.CompletedAt, sessionID, otherID); err != nil {
+		return nil, fmt.Errorf("sessions are not adjacent: %v", err)
+	}
+
+	removedID := otherID
+	if merged.ID == otherID {
+		removedID = sessionID
+	}
+	if err := a.storage.MergePomodoroSessions(merged, removedID); err != nil {
+		return nil, err
+	}
+
+	a.cache.Delete(fmt.Sprintf("sessions:%d", a.currentUser.ID))
+	return a.getSession(merged.ID)
+}
+
+// DeleteSession deletes a recorded session
+func (a *App) DeleteSession(sessionID int64) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	if _, err := a.storage.GetPomodoroSession(sessionID, a.currentUser.ID); err != nil {
+		return err
+	}
+	if err := a.storage.DeletePomodoroSession(sessionID, a.currentUser.ID); err != nil {
+		return err
+	}
+
+	a.cache.Delete(fmt.Sprintf("sessions:%d", a.currentUser.ID))
+	return nil
+}
+
+// getSession loads a recorded session of the current user with its pauses, interruptions
+// and tags
+func (a *App) getSession(sessionID int64) (*PomodoroSession, error) {
+	session, err := a.storage.GetPomodoroSession(sessionID, a.currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []PomodoroSession{*session}
+	if err := a.storage.attachSessionSegments(a.currentUser.ID, sessions); err != nil {
+		return nil, err
+	}
+	if err := a.storage.attachSessionTags(a.currentUser.ID, sessions); err != nil {
+		return nil, err
+	}
+	return &sessions[0], nil
+}
+
+// checkSessionOverlap fails when the time from start to end is shared with a recorded
+// session other than those in ignore, or with the running timer, so no minute is counted
+// twice
+func (a *App) checkSessionOverlap(start, end time.Time, ignore ...int64) error {
+	overlapping, err := a.storage.GetOverlappingSessions(a.currentUser.ID, start, end)
+	if err != nil {
+		return err
+	}
+	for _, session := range overlapping {
+		if !slices.Contains(ignore, session.ID) {
+			return fmt.Errorf("overlaps the session from %s to %s",
+				session.StartedAt.Local().Format("2006-01-02 15:04"), session.CompletedAt.Local().Format("15:04"))
+		}
+	}
+
+	state := a.pomodoroTimer.GetState()
+	focusing := state.Mode == TimerModeStopwatch || state.Phase == PomodoroPhaseWork
+	if state.IsRunning && focusing && state.StartedAt.Before(end) {
+		return fmt.Errorf("overlaps the running timer")
+	}
+	return nil
+}
+
+// ========== Search Methods ==========
+
+// Search runs a full-text search over task titles and descriptions, daily retro
+// notes and session notes, returning highlighted snippets grouped by type
+func (a *App) Search(query string) (*SearchResults, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	return a.storage.Search(a.currentUser.ID, query)
+}
+
+// ========== Reporting Methods ==========
+
+// GetSessions returns Pomodoro sessions within a date range, optionally filtered by tags
+func (a *App) GetSessions(startDate, endDate string, tags []string) ([]PomodoroSession, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return nil, err
+	}
+	// Set end to end of day
+	end = end.Add(24 * time.Hour).Add(-1 * time.Second)
+
+	sessions, err := a.storage.GetSessions(a.currentUser.ID, start, end, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// GetReport generates a report for the current user
+func (a *App) GetReport(startDate, endDate string) (map[string]interface{}, error) {
+	sessions, err := a.GetSessions(startDate, endDate, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := a.getReportTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	totalSessions := len(sessions)
+	totalMinutes := 0
+	taskCounts := make(map[int64]int)
+
+	taskTitles := make(map[int64]string)
+
+	// Pomodoros and time tracked with the stopwatch are also reported apart
+	modeSessions := map[string]int{TimerModePomodoro: 0, TimerModeStopwatch: 0}
+	modeMinutes := map[string]int{TimerModePomodoro: 0, TimerModeStopwatch: 0}
+
+	// Pomodoros run to the end, against those stopped early
+	completedSessions, abandonedSessions := 0, 0
+
+	for _, session := range sessions {
+		totalMinutes += session.Duration
+		modeSessions[session.Mode]++
+		modeMinutes[session.Mode] += session.Duration
+		if session.Mode == TimerModePomodoro {
+			if session.Outcome == SessionOutcomeAbandoned {
+				abandonedSessions++
+			} else {
+				completedSessions++
+			}
+		}
+		if session.TaskID != nil {
+			// Sessions on a subtask also count towards every task above it
+			for _, taskID := range taskLineage(*session.TaskID, tasks) {
+				taskCounts[taskID]++
+				if task, ok := tasks[taskID]; ok {
+					taskTitles[taskID] = task.Title
+				}
+			}
+		}
+	}
+
+	projects, err := a.getProjectReport(sessions, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := a.getTagReport(sessions, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	estimation, err := a.getEstimationReport(sessions, tasks, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	completionRate := 0.0
+	if started := completedSessions + abandonedSessions; started > 0 {
+		completionRate = float64(completedSessions) / float64(started)
+	}
+
+	report := map[string]interface{}{
+		"total_sessions":     totalSessions,
+		"total_minutes":      totalMinutes,
+		"total_hours":        float64(totalMinutes) / 60.0,
+		"mode_sessions":      modeSessions,
+		"mode_minutes":       modeMinutes,
+		"completed_sessions": completedSessions,
+		"abandoned_sessions": abandonedSessions,
+		"completion_rate":    completionRate,
+		"task_counts":        taskCounts,
+		"task_titles":        taskTitles,
+		"projects":           projects,
+		"tags":               tags,
+		"estimation":         estimation,
+		"interruptions":      buildInterruptionReport(sessions, tasks),
+	}
+
+	return report, nil
+}
+
+// ========== Water Reminder Methods ==========
+
+// GetWaterReminderSettings returns water reminder settings
+func (a *App) GetWaterReminderSettings() (*WaterReminderSettings, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	return a.storage.GetWaterReminderSettings(a.currentUser.ID)
+}
+
+// SaveWaterReminderSettings saves water reminder settings
+func (a *App) SaveWaterReminderSettings(enabled bool, intervalMins int, customIntervalMins *int) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	// If custom interval is provided and valid, use it
+	actualInterval := intervalMins
+	if customIntervalMins != nil && *customIntervalMins > 0 {
+		actualInterval = *customIntervalMins
+	}
+
+	settings := &WaterReminderSettings{
+		Enabled:            enabled,
+		IntervalMins:       actualInterval,
+		CustomIntervalMins: customIntervalMins,
+		LastReminder:       time.Now(),
+	}
+
+	if err := a.storage.SaveWaterReminderSettings(a.currentUser.ID, settings); err != nil {
+		log.Print(err.Error())
+		return err
+	}
+
+	// Update reminder
+	if enabled {
+		a.waterReminder.Start(a.currentUser.ID, settings)
+	} else {
+		a.waterReminder.Stop()
+	}
+
+	return nil
+}
+
+// ========== App Info Methods ==========
+
+// GetAppInfo returns application information
+func (a *App) GetAppInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"name":        "Time Tracker",
+		"version":     "1.0.0",
+		"author":      "Pham Van Khang",
+		"email":       "phamvankhang.tvi@gmail.com",
+		"description": "Pomodoro timer and task tracker with water reminder",
+		"license":     "MIT",
+		"repository":  "https://github.com/vkhangstack/time-tracker-desktop",
+	}
+}
+
+// ========== Daily Retro Methods ==========
+
+// DailySummary represents a summary of the day
+type DailySummary struct {
+	Date           string      `json:"date"`
+	CompletedTasks []Task      `json:"completed_tasks"`
+	TotalFocusTime int         `json:"total_focus_time"` // in minutes
+	Retro          *DailyRetro `json:"retro,omitempty"`
+}
+
+// GetDailyRetro returns the daily retro for a specific date
+func (a *App) GetUserDailyRetro(date string) (*DailyRetro, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+	return a.storage.GetDailyRetro(a.currentUser.ID, date)
+}
+
+// SaveDailyRetro saves the daily retro
+func (a *App) SaveDailyRetro(date, retroNotes, planNotes string) error {
+	if a.currentUser == nil {
+		return fmt.Errorf("no user logged in")
+	}
+
+	// Check if exists first to preserve ID if updating
+	existing, _ := a.storage.GetDailyRetro(a.currentUser.ID, date)
+
+	now := time.Now()
+	retro := &DailyRetro{
+		UserID:     a.currentUser.ID,
+		Date:       date,
+		RetroNotes: retroNotes,
+		PlanNotes:  planNotes,
+		UpdatedAt:  now,
+	}
+
+	if existing != nil {
+		retro.ID = existing.ID
+		retro.CreatedAt = existing.CreatedAt
+	} else {
+		retro.ID = GenerateID()
+		retro.CreatedAt = now
+	}
+
+	return a.storage.SaveDailyRetro(retro)
+}
+
+// GetDailySummary returns a summary for the day including tasks, focus time, and retro
+func (a *App) GetDailySummary(date string) (*DailySummary, error) {
+	if a.currentUser == nil {
+		return nil, fmt.Errorf("no user logged in")
+	}
+
+	// Get completed tasks
+	tasks, err := a.storage.GetCompletedTasksForDate(a.currentUser.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get focus time (sum of pomodoro sessions completed on this date)
+	// We need a helper for this or reuse GetSessions but with specific times
+	// For simplicity, let's use GetSessions with start/end of day
+	startTime, _ := time.Parse("2006-01-02", date)
+	endTime := startTime.Add(24 * time.Hour).Add(-1 * time.Second)
+
+	sessions, err := a.storage.GetSessions(a.currentUser.ID, startTime, endTime, nil)
+	if err != nil {
 		return nil, err
 	}
 
@@ -710,7 +2471,11 @@ func (a *App) BackupToDrive() error {
 	if !a.driveService.IsAuthenticated() {
 		return fmt.Errorf("not authenticated")
 	}
-	data, err := a.storage.ExportJSON()
+	includeAttachments, err := a.storage.GetSetting("backup_attachments")
+	if err != nil {
+		return err
+	}
+	data, err := a.storage.ExportJSON(includeAttachments == "true")
 	if err != nil {
 		return fmt.Errorf("failed to export data: %v", err)
 	}
@@ -0,0 +1,119 @@
+package backend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Attachment is a file attached to a task. The file itself is stored once per content
+// under GetAttachmentsDir, named by its SHA-256 hash.
+type Attachment struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	TaskID    int64     `json:"task_id"`
+	Name      string    `json:"name"` // Original file name
+	Hash      string    `json:"hash"` // Hex SHA-256 of the content
+	Size      int64     `json:"size"` // In bytes
+	MimeType  string    `json:"mime_type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AttachmentFile carries the content of an attachment in a backup
+type AttachmentFile struct {
+	Hash string `json:"hash"`
+	Data []byte `json:"data"`
+}
+
+// attachmentPath returns where the content with the given hash is stored. Files are
+// spread over subfolders by the first two characters of their hash.
+func attachmentPath(dir, hash string) (string, error) {
+	if len(hash) != sha256.Size*2 || strings.Trim(hash, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("invalid attachment hash: %s", hash)
+	}
+	return filepath.Join(dir, hash[:2], hash), nil
+}
+
+// fileURL returns the file URL of an absolute path. Windows paths such as C:\dir get a
+// leading slash, so that the drive is not taken for the host.
+func fileURL(path string) string {
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed
+	}
+	u := url.URL{Scheme: "file", Path: slashed}
+	return u.String()
+}
+
+// storeAttachment copies content into the attachment folder and returns its hash and size.
+// Content that is already stored is not written twice.
+func storeAttachment(dir string, content io.Reader) (string, int64, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", 0, err
+	}
+	tmp, err := os.CreateTemp(dir, "upload-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to copy attachment: %v", err)
+	}
+
+	hash := hex.EncodeToString(hasher.Sum(nil))
+	path, err := attachmentPath(dir, hash)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return hash, size, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, err
+	}
+	return hash, size, nil
+}
+
+// removeOrphanedAttachments deletes the stored files whose hash is not referenced, along
+// with leftovers of uploads interrupted over an hour ago, and returns how many files were removed
+func removeOrphanedAttachments(dir string, referenced map[string]bool) (int, error) {
+	removed := 0
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		if referenced[entry.Name()] {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), "upload-") {
+			info, err := entry.Info()
+			if err != nil || time.Since(info.ModTime()) < time.Hour {
+				return nil
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"time"
+)
+
+// Task event actions recorded in the audit log
+const (
+	TaskEventCreate   = "create"
+	TaskEventUpdate   = "update"
+	TaskEventComplete = "complete"
+	TaskEventReopen   = "reopen"
+	TaskEventDelete   = "delete"
+	TaskEventRestore  = "restore"
+)
+
+// TaskEvent is an entry of the task audit log. Before and After hold the values of the
+// fields that changed, keyed by their JSON name; Before is empty for a created task.
+type TaskEvent struct {
+	ID        int64                  `json:"id"`
+	UserID    int64                  `json:"user_id"`
+	TaskID    int64                  `json:"task_id"`
+	TaskTitle string                 `json:"task_title,omitempty"` // Current title, filled in by queries
+	Action    string                 `json:"action"`
+	Before    map[string]interface{} `json:"before,omitempty"`
+	After     map[string]interface{} `json:"after,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// taskSnapshot returns the audited fields of a newly created task
+func taskSnapshot(task *Task) map[string]interface{} {
+	snapshot := map[string]interface{}{
+		"title":       task.Title,
+		"description": task.Description,
+		"completed":   task.Completed,
+		"state":       task.State,
+	}
+	if task.ProjectID != nil {
+		snapshot["project_id"] = *task.ProjectID
+	}
+	if task.ParentID != nil {
+		snapshot["parent_id"] = *task.ParentID
+	}
+	if task.DueDate != "" {
+		snapshot["due_date"] = task.DueDate
+	}
+	if task.Priority != TaskPriorityNone {
+		snapshot["priority"] = task.Priority
+	}
+	if task.EstimatedPomodoros > 0 {
+		snapshot["estimated_pomodoros"] = task.EstimatedPomodoros
+	}
+	if task.Recurrence != "" {
+		snapshot["recurrence"] = task.Recurrence
+	}
+	return snapshot
+}
+
+// auditValue normalizes a column value for the audit log, so that a value read back from
+// SQLite compares equal to the value that was written
+func auditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *int64:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case int:
+		return int64(v)
+	case bool:
+		if v {
+			return int64(1)
+		}
+		return int64(0)
+	case []byte:
+		return string(v)
+	}
+	return value
+}
+
+// sameOptionalID reports whether two optional IDs, such as project IDs, are equal
+func sameOptionalID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
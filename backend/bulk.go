@@ -0,0 +1,8 @@
+package backend
+
+// BulkResult reports the outcome of a bulk operation for one task
+type BulkResult struct {
+	TaskID  int64  `json:"task_id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
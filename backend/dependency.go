@@ -0,0 +1,27 @@
+package backend
+
+// TaskDependency records that a task cannot start before another one is completed
+type TaskDependency struct {
+	TaskID      int64 `json:"task_id"`
+	BlockedByID int64 `json:"blocked_by_id"`
+}
+
+// dependencyPathExists reports whether a task is blocked, directly or through other tasks,
+// by the task with ID to. blockers maps each task ID to the IDs of the tasks blocking it.
+func dependencyPathExists(blockers map[int64][]int64, from, to int64) bool {
+	seen := make(map[int64]bool)
+	pending := []int64{from}
+	for len(pending) > 0 {
+		taskID := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if taskID == to {
+			return true
+		}
+		if seen[taskID] {
+			continue
+		}
+		seen[taskID] = true
+		pending = append(pending, blockers[taskID]...)
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package backend
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IdleDetector tells how long the user has been away from the keyboard and mouse
+type IdleDetector interface {
+	// IdleTime returns the time since the user last did anything, or 0 while they are active
+	IdleTime() (time.Duration, error)
+}
+
+// Ways to settle the time the timer was paused while the user was away
+const (
+	IdleActionKeep     = "keep"     // Count it as focus time after all
+	IdleActionDiscard  = "discard"  // Leave it out, like a pause
+	IdleActionReassign = "reassign" // Count it towards another task
+)
+
+// validateIdleAction checks that action is one of the IdleAction constants
+func validateIdleAction(action string) error {
+	switch action {
+	case IdleActionKeep, IdleActionDiscard, IdleActionReassign:
+		return nil
+	}
+	return fmt.Errorf("invalid idle action: %s", action)
+}
+
+// FakeIdleDetector reports an idle time set by hand, for tests
+type FakeIdleDetector struct {
+	mutex sync.Mutex
+	idle  time.Duration
+	err   error
+}
+
+// SetIdle sets the idle time to report
+func (d *FakeIdleDetector) SetIdle(idle time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.idle = idle
+}
+
+// SetError makes the detector fail with err, or work again when err is nil
+func (d *FakeIdleDetector) SetError(err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.err = err
+}
+
+// IdleTime returns the idle time last set
+func (d *FakeIdleDetector) IdleTime() (time.Duration, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.idle, d.err
+}
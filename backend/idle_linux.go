@@ -0,0 +1,94 @@
+package backend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// logindIdleDetector reads the idle hint of the user's logind session. The desktop sets the
+// hint once its own idle delay has passed, so the idle time it gives is never shorter than
+// that delay.
+type logindIdleDetector struct {
+	mutex   sync.Mutex
+	conn    *dbus.Conn
+	err     error         // Why the system bus could not be reached the last time it was tried
+	retryAt time.Time     // When to try to reach the system bus again
+	backoff time.Duration // How long to wait after the next failed attempt
+}
+
+// idleQueryTimeout bounds a query to logind, so that a bus that does not answer cannot stall
+// the timer loop
+const idleQueryTimeout = 2 * time.Second
+
+// Bounds of the wait before trying to reach the system bus again, which doubles with each
+// failed attempt
+const (
+	idleConnectMinBackoff = 5 * time.Second
+	idleConnectMaxBackoff = 5 * time.Minute
+)
+
+// NewIdleDetector returns the idle detector of the platform
+func NewIdleDetector() IdleDetector {
+	return &logindIdleDetector{}
+}
+
+// IdleTime returns the time since the session went idle, or 0 while it is not idle
+func (d *logindIdleDetector) IdleTime() (time.Duration, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err := d.connect(); err != nil {
+		return 0, err
+	}
+
+	session := d.conn.Object("org.freedesktop.login1", "/org/freedesktop/login1/session/auto")
+	idle, err := d.sessionProperty(session, "IdleHint")
+	if err != nil {
+		return 0, err
+	}
+	if hint, _ := idle.Value().(bool); !hint {
+		return 0, nil
+	}
+	since, err := d.sessionProperty(session, "IdleSinceHint")
+	if err != nil {
+		return 0, err
+	}
+	micros, _ := since.Value().(uint64)
+	return max(0, time.Since(time.UnixMicro(int64(micros)))), nil
+}
+
+// connect connects to the system bus unless already connected. After a failed attempt it
+// keeps returning that error until the backoff has passed.
+func (d *logindIdleDetector) connect() error {
+	if d.conn != nil && d.conn.Connected() {
+		return nil
+	}
+	d.conn = nil
+	now := time.Now()
+	if now.Before(d.retryAt) {
+		return d.err
+	}
+
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		d.backoff = min(max(2*d.backoff, idleConnectMinBackoff), idleConnectMaxBackoff)
+		d.err, d.retryAt = err, now.Add(d.backoff)
+		return err
+	}
+	d.conn, d.err, d.backoff = conn, nil, 0
+	return nil
+}
+
+// sessionProperty reads a property of a logind session
+func (d *logindIdleDetector) sessionProperty(session dbus.BusObject, name string) (dbus.Variant, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), idleQueryTimeout)
+	defer cancel()
+
+	var value dbus.Variant
+	err := session.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0,
+		"org.freedesktop.login1.Session", name).Store(&value)
+	return value, err
+}
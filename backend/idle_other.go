@@ -0,0 +1,23 @@
+//go:build !linux && !windows
+
+package backend
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// unsupportedIdleDetector is used on platforms without idle detection, where the user
+// always counts as present
+type unsupportedIdleDetector struct{}
+
+// NewIdleDetector returns the idle detector of the platform
+func NewIdleDetector() IdleDetector {
+	return unsupportedIdleDetector{}
+}
+
+// IdleTime always fails, as the idle time cannot be read on this platform
+func (unsupportedIdleDetector) IdleTime() (time.Duration, error) {
+	return 0, fmt.Errorf("idle detection is unsupported on %s", runtime.GOOS)
+}
@@ -0,0 +1,320 @@
+package backend
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// goAway ticks once a second while the user stays away, from having been idle for from
+// until they have been idle for to
+func goAway(pt *PomodoroTimer, clock *testClock, detector *FakeIdleDetector, from, to time.Duration) {
+	for away := from + time.Second; away <= to; away += time.Second {
+		detector.SetIdle(away)
+		step(pt, clock, time.Second)
+	}
+}
+
+// comeBack ticks once with the user back at the keyboard since the previous tick
+func comeBack(pt *PomodoroTimer, clock *testClock, detector *FakeIdleDetector) {
+	detector.SetIdle(time.Second)
+	step(pt, clock, time.Second)
+	detector.SetIdle(0)
+}
+
+// startAndWalkAway starts a work phase on a new task, walks away from it 2 minutes in and
+// comes back 7 minutes later. It returns the task and when the phase started.
+func startAndWalkAway(t *testing.T, app *App, pt *PomodoroTimer, clock *testClock, detector *FakeIdleDetector) (*Task, time.Time) {
+	t.Helper()
+	task, err := app.CreateTask("Write report", "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if err := pt.Start(app.currentUser.ID, 25, &task.ID); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	start := clock.Now()
+
+	run(pt, clock, 2*time.Minute)
+	goAway(pt, clock, detector, 0, 7*time.Minute)
+	comeBack(pt, clock, detector)
+	return task, start
+}
+
+// finishPhase runs the phase to its end and returns the sessions recorded
+func finishPhase(t *testing.T, app *App, pt *PomodoroTimer, clock *testClock) []PomodoroSession {
+	t.Helper()
+	if run(pt, clock, time.Hour) {
+		t.Fatal("phase did not end")
+	}
+	sessions, err := app.storage.getAllSessionsForUser(app.currentUser.ID)
+	if err != nil {
+		t.Fatalf("getAllSessionsForUser: %v", err)
+	}
+	return sessions
+}
+
+func TestIdlePausesFromWhenTheUserLeft(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	detector := &FakeIdleDetector{}
+	pt := newTestTimer(app, clock, detector)
+
+	if err := pt.Start(app.currentUser.ID, 25, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	run(pt, clock, 2*time.Minute)
+	left := clock.Now()
+
+	goAway(pt, clock, detector, 0, 5*time.Minute-time.Second)
+	if pt.GetState().IsPaused {
+		t.Fatal("paused before the idle time was reached")
+	}
+	goAway(pt, clock, detector, 5*time.Minute-time.Second, 5*time.Minute)
+	state := pt.GetState()
+	if !state.IsPaused || !state.IdlePaused {
+		t.Fatalf("paused = %v, idle paused = %v; want both", state.IsPaused, state.IdlePaused)
+	}
+	if state.PausedAt == nil || !state.PausedAt.Equal(left) {
+		t.Errorf("PausedAt = %v, want %v", state.PausedAt, left)
+	}
+	if state.Elapsed != 120 {
+		t.Errorf("Elapsed = %d, want 120", state.Elapsed)
+	}
+
+	// Still away: nothing changes
+	goAway(pt, clock, detector, 5*time.Minute, 12*time.Minute)
+	if state := pt.GetState(); state.IdleReturnedAt != nil || !state.PausedAt.Equal(left) {
+		t.Errorf("idle pause moved while the user was away: %+v", state)
+	}
+
+	returned := clock.Now()
+	comeBack(pt, clock, detector)
+	state = pt.GetState()
+	if state.IdleReturnedAt == nil || !state.IdleReturnedAt.Equal(returned) {
+		t.Errorf("IdleReturnedAt = %v, want %v", state.IdleReturnedAt, returned)
+	}
+	if !state.IsPaused {
+		t.Error("timer carried on before the idle time was settled")
+	}
+	if err := pt.SwitchTask(nil); err == nil {
+		t.Error("SwitchTask succeeded before the idle time was settled")
+	}
+}
+
+func TestIdleIsNotWatchedInBreaksOrWhenTheDetectorFails(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	detector := &FakeIdleDetector{}
+	pt := newTestTimer(app, clock, detector)
+
+	detector.SetError(errors.New("no session bus"))
+	if err := pt.Start(app.currentUser.ID, 25, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	goAway(pt, clock, detector, 0, 10*time.Minute)
+	if pt.GetState().IsPaused {
+		t.Error("paused although the idle time could not be read")
+	}
+	detector.SetError(nil)
+
+	pt.SkipPhase()
+	if err := pt.Start(app.currentUser.ID, 0, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	goAway(pt, clock, detector, 0, 4*time.Minute)
+	if state := pt.GetState(); state.Phase != PomodoroPhaseShortBreak || state.IsPaused {
+		t.Errorf("phase = %s, paused = %v; want a running short break", state.Phase, state.IsPaused)
+	}
+}
+
+func TestResolveIdleKeep(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	detector := &FakeIdleDetector{}
+	pt := newTestTimer(app, clock, detector)
+	_, start := startAndWalkAway(t, app, pt, clock, detector)
+
+	if err := app.ResolveIdle(IdleActionKeep, nil); err != nil {
+		t.Fatalf("ResolveIdle: %v", err)
+	}
+	state := pt.GetState()
+	if state.IsPaused || state.IdlePaused || state.IdleReturnedAt != nil || state.PausedAt != nil {
+		t.Errorf("timer still paused: %+v", state)
+	}
+	if state.PausedSeconds != 0 || len(state.TaskSwitches) != 0 {
+		t.Errorf("paused %d seconds with %d task switches, want none", state.PausedSeconds, len(state.TaskSwitches))
+	}
+
+	sessions := finishPhase(t, app, pt, clock)
+	if len(sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(sessions))
+	}
+	if session := sessions[0]; !session.CompletedAt.Equal(start.Add(25*time.Minute)) || session.PausedSeconds != 0 {
+		t.Errorf("session completed at %v after %d paused seconds, want %v and none",
+			session.CompletedAt, session.PausedSeconds, start.Add(25*time.Minute))
+	}
+}
+
+func TestResolveIdleDiscard(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	detector := &FakeIdleDetector{}
+	pt := newTestTimer(app, clock, detector)
+	_, start := startAndWalkAway(t, app, pt, clock, detector)
+	left, returned := start.Add(2*time.Minute), start.Add(9*time.Minute)
+
+	if err := app.ResolveIdle(IdleActionDiscard, nil); err != nil {
+		t.Fatalf("ResolveIdle: %v", err)
+	}
+	state := pt.GetState()
+	if state.IsPaused || state.IdlePaused || state.IdleReturnedAt != nil || state.PausedAt != nil {
+		t.Errorf("timer still paused: %+v", state)
+	}
+	if state.PausedSeconds != 7*60 || len(state.Pauses) != 1 {
+		t.Fatalf("paused %d seconds in %d pauses, want %d in 1", state.PausedSeconds, len(state.Pauses), 7*60)
+	}
+	if pause := state.Pauses[0]; !pause.PausedAt.Equal(left) || !pause.ResumedAt.Equal(returned) {
+		t.Errorf("pause = %v to %v, want %v to %v", pause.PausedAt, pause.ResumedAt, left, returned)
+	}
+	if len(state.TaskSwitches) != 0 {
+		t.Errorf("got %d task switches, want none", len(state.TaskSwitches))
+	}
+
+	sessions := finishPhase(t, app, pt, clock)
+	if len(sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(sessions))
+	}
+	if session := sessions[0]; !session.CompletedAt.Equal(start.Add(32*time.Minute)) || session.PausedSeconds != 7*60 {
+		t.Errorf("session completed at %v after %d paused seconds, want %v and %d",
+			session.CompletedAt, session.PausedSeconds, start.Add(32*time.Minute), 7*60)
+	}
+}
+
+func TestResolveIdleReassign(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	detector := &FakeIdleDetector{}
+	pt := newTestTimer(app, clock, detector)
+	task, start := startAndWalkAway(t, app, pt, clock, detector)
+	left, returned := start.Add(2*time.Minute), start.Add(9*time.Minute)
+	other, err := app.CreateTask("Answer email", "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	if err := app.ResolveIdle(IdleActionReassign, &other.ID); err != nil {
+		t.Fatalf("ResolveIdle: %v", err)
+	}
+	state := pt.GetState()
+	if state.IsPaused || state.IdlePaused || state.PausedSeconds != 0 {
+		t.Errorf("timer still paused: %+v", state)
+	}
+	if state.TaskID == nil || *state.TaskID != task.ID {
+		t.Errorf("TaskID = %v, want the task of the phase, %d", state.TaskID, task.ID)
+	}
+	want := []TaskSwitch{
+		{TaskID: &task.ID, SwitchedAt: left},
+		{TaskID: &other.ID, SwitchedAt: returned},
+	}
+	if len(state.TaskSwitches) != len(want) {
+		t.Fatalf("got %d task switches, want %d", len(state.TaskSwitches), len(want))
+	}
+	for i, taskSwitch := range state.TaskSwitches {
+		if !sameOptionalID(taskSwitch.TaskID, want[i].TaskID) || !taskSwitch.SwitchedAt.Equal(want[i].SwitchedAt) {
+			t.Errorf("task switch %d = %v at %v, want %d at %v",
+				i, taskSwitch.TaskID, taskSwitch.SwitchedAt, *want[i].TaskID, want[i].SwitchedAt)
+		}
+	}
+
+	// The time away goes to the other task, the rest to the task of the phase
+	sessions := finishPhase(t, app, pt, clock)
+	parts := []struct {
+		taskID      int64
+		startedAt   time.Time
+		completedAt time.Time
+	}{
+		{task.ID, start, left},
+		{other.ID, left, returned},
+		{task.ID, returned, start.Add(25 * time.Minute)},
+	}
+	if len(sessions) != len(parts) {
+		t.Fatalf("got %d sessions, want %d", len(sessions), len(parts))
+	}
+	for i, part := range parts {
+		session := sessions[i]
+		if session.TaskID == nil || *session.TaskID != part.taskID ||
+			!session.StartedAt.Equal(part.startedAt) || !session.CompletedAt.Equal(part.completedAt) {
+			t.Errorf("session %d = task %v from %v to %v, want task %d from %v to %v", i,
+				session.TaskID, session.StartedAt, session.CompletedAt, part.taskID, part.startedAt, part.completedAt)
+		}
+	}
+}
+
+func TestResolveIdleReassignToTheSameTask(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	detector := &FakeIdleDetector{}
+	pt := newTestTimer(app, clock, detector)
+	task, _ := startAndWalkAway(t, app, pt, clock, detector)
+
+	if err := app.ResolveIdle(IdleActionReassign, &task.ID); err != nil {
+		t.Fatalf("ResolveIdle: %v", err)
+	}
+	if state := pt.GetState(); state.IsPaused || len(state.TaskSwitches) != 0 {
+		t.Errorf("paused = %v with %d task switches, want a running phase and none", state.IsPaused, len(state.TaskSwitches))
+	}
+}
+
+func TestResolveIdleRejectsUnknownActions(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	detector := &FakeIdleDetector{}
+	pt := newTestTimer(app, clock, detector)
+	startAndWalkAway(t, app, pt, clock, detector)
+
+	if err := app.ResolveIdle("forget", nil); err == nil {
+		t.Fatal("ResolveIdle accepted an unknown action")
+	}
+	if state := pt.GetState(); !state.IsPaused || !state.IdlePaused || state.IdleReturnedAt == nil {
+		t.Errorf("an unknown action changed the idle pause: %+v", state)
+	}
+
+	if err := app.ResolveIdle(IdleActionKeep, nil); err != nil {
+		t.Fatalf("ResolveIdle: %v", err)
+	}
+	if err := app.ResolveIdle(IdleActionKeep, nil); err == nil {
+		t.Error("ResolveIdle succeeded without an idle pause")
+	}
+}
+
+func TestResolveIdleRejectsUnknownAndForeignTasks(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	detector := &FakeIdleDetector{}
+	pt := newTestTimer(app, clock, detector)
+	startAndWalkAway(t, app, pt, clock, detector)
+
+	if err := app.Register("other", "other@example.com", "secret"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	other, err := app.storage.GetUserByUsername("other")
+	if err != nil {
+		t.Fatalf("GetUserByUsername: %v", err)
+	}
+	foreign := &Task{ID: GenerateID(), UserID: other.ID, Title: "Not mine", CreatedAt: time.Now(), Occurrence: 1}
+	if err := app.storage.CreateTask(foreign); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	unknown := GenerateID()
+	for _, taskID := range []*int64{&unknown, &foreign.ID} {
+		if err := app.ResolveIdle(IdleActionReassign, taskID); err == nil {
+			t.Errorf("ResolveIdle reassigned the idle time to task %d", *taskID)
+		}
+	}
+	if state := pt.GetState(); !state.IdlePaused || len(state.TaskSwitches) != 0 {
+		t.Errorf("idle paused = %v with %d task switches, want the idle pause untouched",
+			state.IdlePaused, len(state.TaskSwitches))
+	}
+}
@@ -0,0 +1,39 @@
+package backend
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	user32           = syscall.NewLazyDLL("user32.dll")
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	getLastInputInfo = user32.NewProc("GetLastInputInfo")
+	getTickCount     = kernel32.NewProc("GetTickCount")
+)
+
+// lastInputInfo is the LASTINPUTINFO structure filled in by GetLastInputInfo
+type lastInputInfo struct {
+	size uint32
+	time uint32 // Tick count of the last input
+}
+
+// inputIdleDetector works out the idle time from the last keyboard or mouse input
+type inputIdleDetector struct{}
+
+// NewIdleDetector returns the idle detector of the platform
+func NewIdleDetector() IdleDetector {
+	return inputIdleDetector{}
+}
+
+// IdleTime returns the time since the last input
+func (inputIdleDetector) IdleTime() (time.Duration, error) {
+	info := lastInputInfo{size: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	if ok, _, err := getLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return 0, err
+	}
+	ticks, _, _ := getTickCount.Call()
+	// Both counts wrap around every 49.7 days, which the unsigned difference survives
+	return time.Duration(uint32(ticks)-info.time) * time.Millisecond, nil
+}
@@ -0,0 +1,416 @@
+package backend
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Import formats accepted by ImportTasks
+const (
+	ImportFormatMarkdown = "markdown" // GitHub-style "- [ ] task" checklists, see parseMarkdownChecklist
+	ImportFormatCSV      = "csv"      // Our own CSV schema, see parseTaskCSV
+	ImportFormatTodoist  = "todoist"  // Todoist project CSV export
+	ImportFormatTrello   = "trello"   // Trello board JSON export
+)
+
+// ImportedTask is a task read from an import file, before it is created
+type ImportedTask struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Completed   bool     `json:"completed"`
+	DueDate     string   `json:"due_date,omitempty"` // Format: YYYY-MM-DD
+	Priority    int      `json:"priority"`           // One of the TaskPriority constants
+	Tags        []string `json:"tags"`
+	Parent      int      `json:"parent"`    // Index of the parent in the import, -1 for a top-level task
+	Duplicate   bool     `json:"duplicate"` // The task already exists under the same parent and is skipped
+}
+
+// ImportPreview lists what an import creates and what it skips as duplicates
+type ImportPreview struct {
+	Format  string         `json:"format"`
+	Tasks   []ImportedTask `json:"tasks"`
+	Created int            `json:"created"`
+	Skipped int            `json:"skipped"`
+	DryRun  bool           `json:"dry_run"` // Nothing was written yet
+}
+
+// parseImport reads the tasks of an import file. Parents always come before their subtasks.
+func parseImport(format, data string) ([]ImportedTask, error) {
+	var (
+		tasks []ImportedTask
+		err   error
+	)
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case ImportFormatMarkdown:
+		tasks, err = parseMarkdownChecklist(data)
+	case ImportFormatCSV:
+		tasks, err = parseTaskCSV(data)
+	case ImportFormatTodoist:
+		tasks, err = parseTodoistCSV(data)
+	case ImportFormatTrello:
+		tasks, err = parseTrelloJSON(data)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("no tasks found to import")
+	}
+	for i := range tasks {
+		tasks[i].Tags = normalizeTagNames(tasks[i].Tags)
+		if tasks[i].Tags == nil {
+			tasks[i].Tags = []string{}
+		}
+	}
+	return tasks, nil
+}
+
+// markImportDuplicates flags imported tasks whose title, ignoring case, already exists under
+// the same parent, either in the existing tasks or earlier in the import. It returns for
+// every imported task the ID of the existing task it matches, or -(index+1) of the imported
+// task that will be created for it, so that subtasks can be attached to the right parent.
+func markImportDuplicates(tasks []ImportedTask, existing []Task) []int64 {
+	key := func(parent int64, title string) string {
+		return fmt.Sprintf("%d/%s", parent, strings.ToLower(strings.TrimSpace(title)))
+	}
+
+	seen := make(map[string]int64, len(existing))
+	for _, task := range existing {
+		var parent int64
+		if task.ParentID != nil {
+			parent = *task.ParentID
+		}
+		seen[key(parent, task.Title)] = task.ID
+	}
+
+	targets := make([]int64, len(tasks))
+	for i := range tasks {
+		var parent int64
+		if tasks[i].Parent >= 0 {
+			parent = targets[tasks[i].Parent]
+		}
+		k := key(parent, tasks[i].Title)
+		if target, ok := seen[k]; ok {
+			tasks[i].Duplicate = true
+			targets[i] = target
+			continue
+		}
+		targets[i] = -int64(i + 1)
+		seen[k] = targets[i]
+	}
+	return targets
+}
+
+var markdownChecklistItem = regexp.MustCompile(`^(\s*)[-*+] \[([ xX])\]\s+(.+)$`)
+
+// parseMarkdownChecklist reads GitHub-style task list items such as "- [ ] Write docs" and
+// "- [x] Done". Items indented under another item become its subtasks; other lines are ignored.
+func parseMarkdownChecklist(data string) ([]ImportedTask, error) {
+	var tasks []ImportedTask
+	type level struct {
+		indent int
+		index  int
+	}
+	var stack []level
+
+	for _, line := range strings.Split(data, "\n") {
+		match := markdownChecklistItem.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if match == nil {
+			continue
+		}
+		indent := len(strings.ReplaceAll(match[1], "\t", "    "))
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		task := ImportedTask{
+			Title:     strings.TrimSpace(match[3]),
+			Completed: match[2] != " ",
+			Parent:    -1,
+		}
+		if len(stack) > 0 {
+			task.Parent = stack[len(stack)-1].index
+		}
+		stack = append(stack, level{indent: indent, index: len(tasks)})
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// parseTaskCSV reads our CSV schema. The first row names the columns, in any order and case:
+//
+//	title        required
+//	description
+//	completed    true/false, yes/no or 1/0
+//	due_date     YYYY-MM-DD
+//	priority     none, low, medium, high or 0-3
+//	tags         separated by ";"
+//	parent       title of an earlier row, making this row its subtask
+//
+// Unknown columns are ignored.
+func parseTaskCSV(data string) ([]ImportedTask, error) {
+	rows, columns, err := readImportCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("CSV has no title column")
+	}
+
+	var tasks []ImportedTask
+	byTitle := make(map[string]int)
+	for i, row := range rows {
+		line := i + 2
+		task := ImportedTask{
+			Title:       csvField(row, columns, "title"),
+			Description: csvField(row, columns, "description"),
+			Tags:        strings.Split(csvField(row, columns, "tags"), ";"),
+			Parent:      -1,
+		}
+		if task.Title == "" {
+			continue
+		}
+
+		if value := csvField(row, columns, "completed"); value != "" {
+			switch strings.ToLower(value) {
+			case "true", "yes", "1", "x":
+				task.Completed = true
+			case "false", "no", "0":
+			default:
+				return nil, fmt.Errorf("line %d: invalid completed value %q", line, value)
+			}
+		}
+		if value := csvField(row, columns, "due_date"); value != "" {
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				return nil, fmt.Errorf("line %d: invalid due date %q, expected YYYY-MM-DD", line, value)
+			}
+			task.DueDate = value
+		}
+		if value := csvField(row, columns, "priority"); value != "" {
+			if task.Priority, err = parseImportPriority(value); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		if value := csvField(row, columns, "parent"); value != "" {
+			parent, ok := byTitle[strings.ToLower(value)]
+			if !ok {
+				return nil, fmt.Errorf("line %d: parent %q must appear on an earlier line", line, value)
+			}
+			task.Parent = parent
+		}
+
+		byTitle[strings.ToLower(task.Title)] = len(tasks)
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// parseImportPriority reads a priority given by name or number
+func parseImportPriority(value string) (int, error) {
+	switch strings.ToLower(value) {
+	case "none", "":
+		return TaskPriorityNone, nil
+	case "low":
+		return TaskPriorityLow, nil
+	case "medium":
+		return TaskPriorityMedium, nil
+	case "high":
+		return TaskPriorityHigh, nil
+	}
+	priority, err := strconv.Atoi(value)
+	if err != nil || priority < TaskPriorityNone || priority > TaskPriorityHigh {
+		return 0, fmt.Errorf("invalid priority %q", value)
+	}
+	return priority, nil
+}
+
+// todoistLabel matches an "@label" in the content of a Todoist task
+var todoistLabel = regexp.MustCompile(`(^|\s)@([\p{L}\p{N}_-]+)`)
+
+// parseTodoistCSV reads a Todoist project export. Only rows of TYPE "task" are imported;
+// INDENT nests tasks, PRIORITY 1 (p1) is the most urgent and "@labels" become tags.
+// Due dates are kept when DATE is a plain date, since recurring dates have no equivalent.
+func parseTodoistCSV(data string) ([]ImportedTask, error) {
+	rows, columns, err := readImportCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := columns["content"]; !ok {
+		return nil, fmt.Errorf("not a Todoist export: no CONTENT column")
+	}
+
+	var tasks []ImportedTask
+	var stack []int // Index of the last task at each indent
+	for _, row := range rows {
+		if !strings.EqualFold(csvField(row, columns, "type"), "task") {
+			continue
+		}
+
+		content := csvField(row, columns, "content")
+		var tags []string
+		for _, match := range todoistLabel.FindAllStringSubmatch(content, -1) {
+			tags = append(tags, match[2])
+		}
+		task := ImportedTask{
+			Title:       strings.TrimSpace(todoistLabel.ReplaceAllString(content, "$1")),
+			Description: csvField(row, columns, "description"),
+			Tags:        tags,
+			Parent:      -1,
+		}
+		if task.Title == "" {
+			continue
+		}
+
+		switch csvField(row, columns, "priority") {
+		case "1":
+			task.Priority = TaskPriorityHigh
+		case "2":
+			task.Priority = TaskPriorityMedium
+		case "3":
+			task.Priority = TaskPriorityLow
+		}
+		if date := csvField(row, columns, "date"); len(date) >= 10 {
+			if _, err := time.Parse("2006-01-02", date[:10]); err == nil {
+				task.DueDate = date[:10]
+			}
+		}
+
+		indent, err := strconv.Atoi(csvField(row, columns, "indent"))
+		if err != nil || indent < 1 {
+			indent = 1
+		}
+		if indent > len(stack)+1 {
+			indent = len(stack) + 1
+		}
+		stack = stack[:indent-1]
+		if len(stack) > 0 {
+			task.Parent = stack[len(stack)-1]
+		}
+		stack = append(stack, len(tasks))
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// readImportCSV reads a CSV file with a header row, returning the data rows and the
+// position of each column by its lowercased name
+func readImportCSV(data string) ([][]string, map[string]int, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\ufeff")))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("CSV is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return records[1:], columns, nil
+}
+
+// csvField returns the trimmed value of a named column, or "" when the row lacks it
+func csvField(row []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// trelloBoard holds the parts of a Trello board export that are imported
+type trelloBoard struct {
+	Cards []struct {
+		ID          string  `json:"id"`
+		Name        string  `json:"name"`
+		Desc        string  `json:"desc"`
+		Closed      bool    `json:"closed"`
+		Due         *string `json:"due"`
+		DueComplete bool    `json:"dueComplete"`
+		Pos         float64 `json:"pos"`
+		Labels      []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string  `json:"idCard"`
+		Pos        float64 `json:"pos"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+// parseTrelloJSON reads a Trello board export. Every open card becomes a task with its
+// labels as tags, and the items of its checklists become subtasks. Archived cards are left out.
+func parseTrelloJSON(data string) ([]ImportedTask, error) {
+	var board trelloBoard
+	if err := json.Unmarshal([]byte(data), &board); err != nil {
+		return nil, fmt.Errorf("not a Trello export: %v", err)
+	}
+
+	sort.SliceStable(board.Cards, func(i, j int) bool { return board.Cards[i].Pos < board.Cards[j].Pos })
+	sort.SliceStable(board.Checklists, func(i, j int) bool { return board.Checklists[i].Pos < board.Checklists[j].Pos })
+
+	var tasks []ImportedTask
+	for _, card := range board.Cards {
+		title := strings.TrimSpace(card.Name)
+		if card.Closed || title == "" {
+			continue
+		}
+
+		task := ImportedTask{
+			Title:       title,
+			Description: card.Desc,
+			Completed:   card.DueComplete,
+			Parent:      -1,
+		}
+		for _, label := range card.Labels {
+			if label.Name != "" {
+				task.Tags = append(task.Tags, label.Name)
+			} else {
+				task.Tags = append(task.Tags, label.Color)
+			}
+		}
+		if card.Due != nil {
+			if due, err := time.Parse(time.RFC3339, *card.Due); err == nil {
+				task.DueDate = due.Local().Format("2006-01-02")
+			}
+		}
+
+		parent := len(tasks)
+		tasks = append(tasks, task)
+
+		for _, checklist := range board.Checklists {
+			if checklist.IDCard != card.ID {
+				continue
+			}
+			items := checklist.CheckItems
+			sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+			for _, item := range items {
+				if name := strings.TrimSpace(item.Name); name != "" {
+					tasks = append(tasks, ImportedTask{
+						Title:     name,
+						Completed: item.State == "complete",
+						Parent:    parent,
+					})
+				}
+			}
+		}
+	}
+	return tasks, nil
+}
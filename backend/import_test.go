@@ -0,0 +1,319 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+)
+
+// checkImport compares parsed tasks with the expected ones. Tags are compared as
+// parseImport leaves them, normalized.
+func checkImport(t *testing.T, got, want []ImportedTask) {
+	t.Helper()
+	for i := range got {
+		got[i].Tags = normalizeTagNames(got[i].Tags)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseMarkdownChecklist(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []ImportedTask
+	}{
+		{
+			name: "flat list with other lines",
+			data: "# Sprint\n- [ ] Write docs\nsome text\n* [x] Ship it\r\n+ [X] Celebrate\n- not a task",
+			want: []ImportedTask{
+				{Title: "Write docs", Parent: -1},
+				{Title: "Ship it", Completed: true, Parent: -1},
+				{Title: "Celebrate", Completed: true, Parent: -1},
+			},
+		},
+		{
+			name: "nesting by indent",
+			data: "- [ ] Release\n  - [ ] Build\n    - [x] Linux\n  - [ ] Tag\n- [ ] Announce\n\t- [ ] Blog post",
+			want: []ImportedTask{
+				{Title: "Release", Parent: -1},
+				{Title: "Build", Parent: 0},
+				{Title: "Linux", Completed: true, Parent: 1},
+				{Title: "Tag", Parent: 0},
+				{Title: "Announce", Parent: -1},
+				{Title: "Blog post", Parent: 4},
+			},
+		},
+		{
+			name: "no items",
+			data: "Just some notes",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMarkdownChecklist(tt.data)
+			if err != nil {
+				t.Fatalf("parseMarkdownChecklist: %v", err)
+			}
+			checkImport(t, got, tt.want)
+		})
+	}
+}
+
+func TestParseTaskCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []ImportedTask
+		wantErr bool
+	}{
+		{
+			name: "all columns in any order",
+			data: "Priority,Title,Completed,Due_Date,Tags,Description,Extra\n" +
+				"high,Write docs,yes,2026-03-10,Work; Docs,The user guide,ignored\n" +
+				"2,Review,0,,,,\n",
+			want: []ImportedTask{
+				{Title: "Write docs", Description: "The user guide", Completed: true, DueDate: "2026-03-10",
+					Priority: TaskPriorityHigh, Tags: []string{"work", "docs"}, Parent: -1},
+				{Title: "Review", Priority: TaskPriorityMedium, Parent: -1},
+			},
+		},
+		{
+			name: "parents by title of an earlier row",
+			data: "title,parent\nRelease,\nBuild,release\nLinux,Build\n,Release\nTag,Release\n",
+			want: []ImportedTask{
+				{Title: "Release", Parent: -1},
+				{Title: "Build", Parent: 0},
+				{Title: "Linux", Parent: 1},
+				{Title: "Tag", Parent: 0},
+			},
+		},
+		{
+			name:    "parent on a later row",
+			data:    "title,parent\nBuild,Release\nRelease,\n",
+			wantErr: true,
+		},
+		{
+			name:    "no title column",
+			data:    "name\nWrite docs\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid completed value",
+			data:    "title,completed\nWrite docs,maybe\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid due date",
+			data:    "title,due_date\nWrite docs,10/03/2026\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid priority",
+			data:    "title,priority\nWrite docs,urgent\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTaskCSV(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseTaskCSV succeeded with %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTaskCSV: %v", err)
+			}
+			checkImport(t, got, tt.want)
+		})
+	}
+}
+
+func TestParseTodoistCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []ImportedTask
+		wantErr bool
+	}{
+		{
+			name: "tasks, labels and priorities",
+			data: "\ufeffTYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,DATE\n" +
+				"section,Backlog,,,,\n" +
+				"task,Write docs @work @docs,The user guide,1,1,2026-03-10\n" +
+				"note,Remember the index,,,,\n" +
+				"task,Review,,3,1,every monday\n" +
+				"task,Plain,,4,1,\n",
+			want: []ImportedTask{
+				{Title: "Write docs", Description: "The user guide", DueDate: "2026-03-10",
+					Priority: TaskPriorityHigh, Tags: []string{"work", "docs"}, Parent: -1},
+				{Title: "Review", Priority: TaskPriorityLow, Parent: -1},
+				{Title: "Plain", Parent: -1},
+			},
+		},
+		{
+			name: "nesting by indent",
+			data: "TYPE,CONTENT,INDENT\n" +
+				"task,Release,1\n" +
+				"task,Build,2\n" +
+				"task,Linux,3\n" +
+				"task,Tag,2\n" +
+				"task,Too deep,5\n" +
+				"task,Announce,1\n" +
+				"task,No indent,\n",
+			want: []ImportedTask{
+				{Title: "Release", Parent: -1},
+				{Title: "Build", Parent: 0},
+				{Title: "Linux", Parent: 1},
+				{Title: "Tag", Parent: 0},
+				{Title: "Too deep", Parent: 3},
+				{Title: "Announce", Parent: -1},
+				{Title: "No indent", Parent: -1},
+			},
+		},
+		{
+			name:    "not a Todoist export",
+			data:    "title\nWrite docs\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTodoistCSV(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseTodoistCSV succeeded with %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTodoistCSV: %v", err)
+			}
+			checkImport(t, got, tt.want)
+		})
+	}
+}
+
+func TestParseTrelloJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []ImportedTask
+		wantErr bool
+	}{
+		{
+			name: "cards in position order with their checklists",
+			data: `{
+				"cards": [
+					{"id": "b", "name": "Release", "pos": 2, "dueComplete": true,
+					 "labels": [{"name": "Work"}, {"name": "", "color": "red"}]},
+					{"id": "a", "name": "Write docs", "desc": "The user guide", "pos": 1,
+					 "due": "2026-03-10T12:00:00.000Z"},
+					{"id": "c", "name": "Old", "pos": 3, "closed": true},
+					{"id": "d", "name": "  ", "pos": 4}
+				],
+				"checklists": [
+					{"idCard": "b", "pos": 2, "checkItems": [{"name": "Tag", "pos": 1}]},
+					{"idCard": "b", "pos": 1, "checkItems": [
+						{"name": "Linux", "state": "complete", "pos": 2},
+						{"name": "Build", "state": "incomplete", "pos": 1}
+					]},
+					{"idCard": "c", "pos": 1, "checkItems": [{"name": "Lost", "pos": 1}]}
+				]
+			}`,
+			want: []ImportedTask{
+				{Title: "Write docs", Description: "The user guide", DueDate: "2026-03-10", Parent: -1},
+				{Title: "Release", Completed: true, Tags: []string{"work", "red"}, Parent: -1},
+				{Title: "Build", Parent: 1},
+				{Title: "Linux", Completed: true, Parent: 1},
+				{Title: "Tag", Parent: 1},
+			},
+		},
+		{
+			name:    "not JSON",
+			data:    "title\nWrite docs\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTrelloJSON(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseTrelloJSON succeeded with %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTrelloJSON: %v", err)
+			}
+			checkImport(t, got, tt.want)
+		})
+	}
+}
+
+func TestMarkImportDuplicates(t *testing.T) {
+	release := int64(100)
+	existing := []Task{
+		{ID: release, Title: "Release"},
+		{ID: 101, Title: "Build", ParentID: &release},
+		{ID: 102, Title: "Docs"},
+	}
+
+	tests := []struct {
+		name           string
+		tasks          []ImportedTask
+		wantTargets    []int64
+		wantDuplicates []bool
+	}{
+		{
+			name: "existing tasks match by title and parent, ignoring case",
+			tasks: []ImportedTask{
+				{Title: " release ", Parent: -1},
+				{Title: "BUILD", Parent: 0},
+				{Title: "Tag", Parent: 0},
+				{Title: "Build", Parent: -1},
+			},
+			wantTargets:    []int64{release, 101, -3, -4},
+			wantDuplicates: []bool{true, true, false, false},
+		},
+		{
+			name: "subtasks of a new parent are new",
+			tasks: []ImportedTask{
+				{Title: "Launch", Parent: -1},
+				{Title: "Docs", Parent: 0},
+				{Title: "Build", Parent: 0},
+			},
+			wantTargets:    []int64{-1, -2, -3},
+			wantDuplicates: []bool{false, false, false},
+		},
+		{
+			name: "repeats within the import",
+			tasks: []ImportedTask{
+				{Title: "Launch", Parent: -1},
+				{Title: "Blog post", Parent: 0},
+				{Title: "launch", Parent: -1},
+				{Title: "Blog Post", Parent: 2},
+				{Title: "Tweet", Parent: 2},
+			},
+			wantTargets:    []int64{-1, -2, -1, -2, -5},
+			wantDuplicates: []bool{false, false, true, true, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets := markImportDuplicates(tt.tasks, existing)
+			if !reflect.DeepEqual(targets, tt.wantTargets) {
+				t.Errorf("targets = %v, want %v", targets, tt.wantTargets)
+			}
+			for i, task := range tt.tasks {
+				if task.Duplicate != tt.wantDuplicates[i] {
+					t.Errorf("task %d %q: duplicate = %v, want %v", i, task.Title, task.Duplicate, tt.wantDuplicates[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of interruption logged during a pomodoro
+const (
+	InterruptionInternal = "internal" // The user's own distraction, such as checking mail
+	InterruptionExternal = "external" // Someone or something else, such as a call
+)
+
+// maxInterruptionReasons is the number of most common reasons listed in a report entry
+const maxInterruptionReasons = 3
+
+// PauseSegment is a stretch of time a pomodoro spent paused
+type PauseSegment struct {
+	PausedAt  time.Time `json:"paused_at"`
+	ResumedAt time.Time `json:"resumed_at"`
+}
+
+// Interruption is an interruption logged during a running pomodoro
+type Interruption struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"user_id"`
+	SessionID  int64     `json:"session_id"` // Set once the pomodoro is recorded
+	Kind       string    `json:"kind"`       // One of the Interruption constants
+	Note       string    `json:"note,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// validateInterruptionKind checks that kind is one of the Interruption constants
+func validateInterruptionKind(kind string) error {
+	if kind != InterruptionInternal && kind != InterruptionExternal {
+		return fmt.Errorf("invalid interruption kind: %s", kind)
+	}
+	return nil
+}
+
+// InterruptionReason counts how often a reason was given for an interruption
+type InterruptionReason struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// InterruptionStats summarizes the interruptions and pauses of the sessions of a day or task
+type InterruptionStats struct {
+	Date          string               `json:"date,omitempty"`    // Set for per-day entries
+	TaskID        int64                `json:"task_id,omitempty"` // Set for per-task entries
+	Title         string               `json:"title,omitempty"`
+	Interruptions int                  `json:"interruptions"`
+	Internal      int                  `json:"internal"`
+	External      int                  `json:"external"`
+	PausedSeconds int                  `json:"paused_seconds"`
+	TopReasons    []InterruptionReason `json:"top_reasons"`
+
+	reasons map[string]*InterruptionReason
+}
+
+// InterruptionReport breaks down interruptions and paused time per day and per task
+type InterruptionReport struct {
+	Interruptions int                 `json:"interruptions"`
+	PausedSeconds int                 `json:"paused_seconds"`
+	Days          []InterruptionStats `json:"days"`
+	Tasks         []InterruptionStats `json:"tasks"`
+}
+
+// add counts the pauses and interruptions of a session
+func (s *InterruptionStats) add(session PomodoroSession) {
+	s.PausedSeconds += session.PausedSeconds
+	for _, interruption := range session.Interruptions {
+		s.Interruptions++
+		if interruption.Kind == InterruptionExternal {
+			s.External++
+		} else {
+			s.Internal++
+		}
+
+		// Reasons are compared ignoring case and surrounding space, keeping the first spelling
+		reason := strings.TrimSpace(interruption.Note)
+		if reason == "" {
+			continue
+		}
+		key := strings.ToLower(reason)
+		if s.reasons == nil {
+			s.reasons = make(map[string]*InterruptionReason)
+		}
+		if entry, ok := s.reasons[key]; ok {
+			entry.Count++
+		} else {
+			s.reasons[key] = &InterruptionReason{Reason: reason, Count: 1}
+		}
+	}
+}
+
+// finish lists the most common reasons, most frequent first
+func (s *InterruptionStats) finish() {
+	s.TopReasons = []InterruptionReason{}
+	for _, entry := range s.reasons {
+		s.TopReasons = append(s.TopReasons, *entry)
+	}
+	sort.Slice(s.TopReasons, func(i, j int) bool {
+		if s.TopReasons[i].Count != s.TopReasons[j].Count {
+			return s.TopReasons[i].Count > s.TopReasons[j].Count
+		}
+		return s.TopReasons[i].Reason < s.TopReasons[j].Reason
+	})
+	if len(s.TopReasons) > maxInterruptionReasons {
+		s.TopReasons = s.TopReasons[:maxInterruptionReasons]
+	}
+	s.reasons = nil
+}
+
+// buildInterruptionReport groups the interruptions and paused time of sessions by the day
+// they were completed and by their task
+func buildInterruptionReport(sessions []PomodoroSession, tasks map[int64]Task) *InterruptionReport {
+	byDay := make(map[string]*InterruptionStats)
+	byTask := make(map[int64]*InterruptionStats)
+	for _, session := range sessions {
+		if session.PausedSeconds == 0 && len(session.Interruptions) == 0 {
+			continue
+		}
+
+		date := session.CompletedAt.Format("2006-01-02")
+		day, ok := byDay[date]
+		if !ok {
+			day = &InterruptionStats{Date: date}
+			byDay[date] = day
+		}
+		day.add(session)
+
+		if session.TaskID != nil {
+			entry, ok := byTask[*session.TaskID]
+			if !ok {
+				entry = &InterruptionStats{TaskID: *session.TaskID, Title: tasks[*session.TaskID].Title}
+				byTask[*session.TaskID] = entry
+			}
+			entry.add(session)
+		}
+	}
+
+	report := &InterruptionReport{Days: []InterruptionStats{}, Tasks: []InterruptionStats{}}
+	for _, day := range byDay {
+		day.finish()
+		report.Interruptions += day.Interruptions
+		report.PausedSeconds += day.PausedSeconds
+		report.Days = append(report.Days, *day)
+	}
+	for _, entry := range byTask {
+		entry.finish()
+		report.Tasks = append(report.Tasks, *entry)
+	}
+
+	sort.Slice(report.Days, func(i, j int) bool {
+		return report.Days[i].Date < report.Days[j].Date
+	})
+	sort.Slice(report.Tasks, func(i, j int) bool {
+		if report.Tasks[i].Interruptions != report.Tasks[j].Interruptions {
+			return report.Tasks[i].Interruptions > report.Tasks[j].Interruptions
+		}
+		return report.Tasks[i].PausedSeconds > report.Tasks[j].PausedSeconds
+	})
+	return report
+}
@@ -0,0 +1,120 @@
+package backend
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// overdueCheckInterval is how often the overdue reminder looks for overdue tasks
+const overdueCheckInterval = time.Hour
+
+// OverdueReminder notifies the user about overdue tasks at most once per day
+type OverdueReminder struct {
+	ticker    *time.Ticker
+	stopChan  chan bool
+	isRunning bool
+	mutex     sync.Mutex
+	app       *App
+	userID    int64
+}
+
+// NewOverdueReminder creates a new OverdueReminder
+func NewOverdueReminder(app *App) *OverdueReminder {
+	return &OverdueReminder{
+		app: app,
+	}
+}
+
+// Start starts checking for overdue tasks of a user
+func (od *OverdueReminder) Start(userID int64) {
+	od.mutex.Lock()
+	defer od.mutex.Unlock()
+
+	if od.isRunning {
+		od.stop()
+	}
+
+	od.userID = userID
+	od.isRunning = true
+	od.stopChan = make(chan bool)
+	od.ticker = time.NewTicker(overdueCheckInterval)
+
+	go od.run()
+}
+
+// run is the main reminder loop
+func (od *OverdueReminder) run() {
+	od.check()
+	for {
+		select {
+		case <-od.ticker.C:
+			od.check()
+		case <-od.stopChan:
+			return
+		}
+	}
+}
+
+// check sends a notification if there are overdue tasks and none was sent today
+func (od *OverdueReminder) check() {
+	od.mutex.Lock()
+	userID := od.userID
+	od.mutex.Unlock()
+
+	if od.app.storage == nil {
+		return
+	}
+
+	today := time.Now().Format("2006-01-02")
+	settingKey := fmt.Sprintf("overdue_notified:%d", userID)
+	lastNotified, err := od.app.storage.GetSetting(settingKey)
+	if err != nil || lastNotified == today {
+		return
+	}
+
+	tasks, err := od.app.storage.GetTasks(userID, TaskFilter{Mode: TaskModeOverdue})
+	if err != nil || len(tasks) == 0 {
+		return
+	}
+
+	if err := od.app.storage.SaveSetting(settingKey, today); err != nil {
+		log.Printf("save overdue notification date: %v", err)
+	}
+
+	if od.app.ctx != nil {
+		runtime.EventsEmit(od.app.ctx, "tasks:overdue", len(tasks))
+	}
+
+	message := fmt.Sprintf("You have %d overdue tasks.", len(tasks))
+	if len(tasks) == 1 {
+		message = fmt.Sprintf("\"%s\" is overdue.", tasks[0].Title)
+	}
+	err = od.app.PushNotification(&Notification{
+		AppID:   "Time Tracker",
+		Title:   "Overdue Tasks",
+		Message: message,
+	})
+	if err != nil {
+		log.Printf("push overdue notification: %v", err)
+	}
+}
+
+// Stop stops the overdue reminder
+func (od *OverdueReminder) Stop() {
+	od.mutex.Lock()
+	defer od.mutex.Unlock()
+	od.stop()
+}
+
+// stop stops the overdue reminder (internal, no lock)
+func (od *OverdueReminder) stop() {
+	if od.isRunning {
+		od.ticker.Stop()
+		close(od.stopChan)
+		od.isRunning = false
+	}
+}
@@ -63,3 +63,12 @@ func GetConfigPath() (string, error) {
 	}
 	return filepath.Join(dataDir, "config.json"), nil
 }
+
+// GetAttachmentsDir returns the folder holding the files attached to tasks
+func GetAttachmentsDir() (string, error) {
+	dataDir, err := GetDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "attachments"), nil
+}
@@ -1,36 +1,83 @@
 package backend
 
 import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// PomodoroTimer manages the Pomodoro timer state
+// PomodoroTimer manages the Pomodoro timer state and walks through the cycle of work
+// phases, short breaks and long breaks
 type PomodoroTimer struct {
 	state    *TimerState
+	userID   int64 // User the timer runs for
+	settings PomodoroSettings
 	ticker   *time.Ticker
 	stopChan chan bool
+	lastTick time.Time // When the running phase was last brought up to date
+	now      func() time.Time
+	idle     IdleDetector // Tells when the user walks away from a running work phase
 	mutex    sync.RWMutex
 	app      *App
 }
 
+// suspendGap is the longest time expected between two ticks. A longer gap means the
+// machine was asleep.
+const suspendGap = 15 * time.Second
+
+// wallClock returns the current time without its monotonic clock reading, so that time
+// differences include the time the machine spent asleep
+func wallClock() time.Time {
+	return time.Now().Round(0)
+}
+
 // NewPomodoroTimer creates a new PomodoroTimer
 func NewPomodoroTimer(app *App) *PomodoroTimer {
+	return newPomodoroTimer(app, wallClock, NewIdleDetector())
+}
+
+// newPomodoroTimer creates a PomodoroTimer that reads the time from now and notices the
+// user going idle with idle
+func newPomodoroTimer(app *App, now func() time.Time, idle IdleDetector) *PomodoroTimer {
 	return &PomodoroTimer{
-		state: &TimerState{
-			IsRunning:     false,
-			IsPaused:      false,
-			Duration:      0,
-			TimeRemaining: 0,
-		},
-		app: app,
+		state:    newTimerState(),
+		settings: defaultPomodoroSettings,
+		now:      now,
+		idle:     idle,
+		app:      app,
+	}
+}
+
+// newTimerState returns the state of a timer that has not run yet
+func newTimerState() *TimerState {
+	return &TimerState{
+		IsRunning:      false,
+		IsPaused:       false,
+		Duration:       0,
+		TimeRemaining:  0,
+		Mode:           TimerModePomodoro,
+		Phase:          PomodoroPhaseWork,
+		LongBreakEvery: defaultPomodoroSettings.LongBreakEvery,
 	}
 }
 
-// Start starts the Pomodoro timer
-func (pt *PomodoroTimer) Start(durationMinutes int, taskID *int64) error {
+// SetSettings changes the cycle settings. A running phase keeps its length.
+func (pt *PomodoroTimer) SetSettings(settings PomodoroSettings) {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	pt.settings = settings
+	pt.state.LongBreakEvery = settings.LongBreakEvery
+}
+
+// Start starts the upcoming phase of the cycle for a user. durationMinutes overrides the
+// length of a work phase when positive; breaks always use the configured length.
+func (pt *PomodoroTimer) Start(userID int64, durationMinutes int, taskID *int64) error {
 	pt.mutex.Lock()
 	defer pt.mutex.Unlock()
 
@@ -38,54 +85,289 @@ func (pt *PomodoroTimer) Start(durationMinutes int, taskID *int64) error {
 		return nil
 	}
 
+	if pt.state.Phase != PomodoroPhaseWork || durationMinutes <= 0 {
+		durationMinutes = pt.settings.phaseMinutes(pt.state.Phase)
+	}
+	pt.userID = userID
+	pt.state.Mode = TimerModePomodoro
+	pt.state.TaskID = taskID
+	pt.begin(durationMinutes)
+
+	return nil
+}
+
+// StartStopwatch starts counting up for a user until the timer is stopped. The stopwatch
+// runs outside of the cycle, which carries on where it was once it stops.
+func (pt *PomodoroTimer) StartStopwatch(userID int64, taskID *int64) error {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	if pt.state.IsRunning {
+		return nil
+	}
+
+	pt.userID = userID
+	pt.state.Mode = TimerModeStopwatch
+	pt.state.TaskID = taskID
+	pt.begin(0)
+
+	return nil
+}
+
+// begin starts counting down the current phase. The caller holds the mutex.
+func (pt *PomodoroTimer) begin(durationMinutes int) {
 	pt.state.IsRunning = true
 	pt.state.IsPaused = false
 	pt.state.Duration = durationMinutes * 60
 	pt.state.TimeRemaining = durationMinutes * 60
-	pt.state.TaskID = taskID
-	pt.state.StartedAt = time.Now()
+	pt.state.Elapsed = 0
+	pt.state.StartedAt = pt.now()
+	pt.clearPauses()
 
+	pt.startTicking()
+	pt.persist()
+}
+
+// startTicking starts the loop counting down the current phase. The caller holds the mutex.
+func (pt *PomodoroTimer) startTicking() {
 	pt.stopChan = make(chan bool)
 	pt.ticker = time.NewTicker(1 * time.Second)
+	pt.lastTick = pt.now()
 
-	go pt.run()
+	go pt.run(pt.ticker, pt.stopChan)
+}
 
-	return nil
+// stopTicking ends the loop counting down the current phase, unless it has already been
+// ended. The caller holds the mutex.
+func (pt *PomodoroTimer) stopTicking() {
+	if pt.stopChan == nil {
+		return
+	}
+	pt.ticker.Stop()
+	close(pt.stopChan)
+	pt.stopChan = nil
 }
 
-// run is the main timer loop
-func (pt *PomodoroTimer) run() {
+// run is the main timer loop. The ticker only wakes it up; the time left is worked out
+// from the clock, so late ticks do not make the timer drift. The idle time is read before
+// each tick, as the detector may take a while to answer and must not hold up the timer.
+func (pt *PomodoroTimer) run(ticker *time.Ticker, stopChan chan bool) {
 	for {
 		select {
-		case <-pt.ticker.C:
-			pt.mutex.Lock()
-			if !pt.state.IsPaused {
-				pt.state.TimeRemaining--
-				if pt.state.TimeRemaining <= 0 {
-					pt.mutex.Unlock()
-					pt.complete()
-					return
-				}
+		case <-ticker.C:
+			idle, idleKnown := pt.readIdle()
+			if !pt.tick(stopChan, idle, idleKnown) {
+				return
 			}
-			pt.mutex.Unlock()
-		case <-pt.stopChan:
+		case <-stopChan:
 			return
 		}
 	}
 }
 
-// complete handles timer completion
-func (pt *PomodoroTimer) complete() {
+// tick brings the running phase up to date with the clock, completing it once its time is
+// up, and reports whether it is still running. stopChan identifies the run that ticked, so
+// a phase stopped in the meantime is left alone. idle is how long the user has been idle,
+// if idleKnown.
+func (pt *PomodoroTimer) tick(stopChan chan bool, idle time.Duration, idleKnown bool) bool {
 	pt.mutex.Lock()
 	defer pt.mutex.Unlock()
 
+	if pt.stopChan != stopChan || !pt.state.IsRunning {
+		return false
+	}
+
+	now := pt.now()
+	if gap := now.Sub(pt.lastTick); gap > suspendGap {
+		pt.resumeFromSuspend(pt.lastTick, now)
+	}
+	pt.lastTick = now
+	if pt.focusing() && idleKnown {
+		pt.watchIdle(now, idle)
+	}
+
+	pt.state.Elapsed = int(pt.elapsed(now).Seconds())
+	if pt.state.Mode == TimerModeStopwatch {
+		pt.emit("timer:tick", *pt.state)
+		return true
+	}
+
+	remaining := pt.remaining(now)
+	if remaining > 0 {
+		pt.state.TimeRemaining = secondsLeft(remaining)
+		pt.emit("timer:tick", *pt.state)
+		return true
+	}
+	pt.complete(now.Add(remaining))
+	return false
+}
+
+// resumeFromSuspend accounts for the machine sleeping between two ticks. No work gets done
+// while asleep, so the sleep counts as a pause of focus time; breaks carry on meanwhile.
+// The caller holds the mutex.
+func (pt *PomodoroTimer) resumeFromSuspend(sleptAt, wokeAt time.Time) {
+	if pt.focusing() && !pt.state.IsPaused {
+		pt.state.PausedSeconds += int(wokeAt.Sub(sleptAt).Seconds())
+		pt.state.Pauses = append(pt.state.Pauses, PauseSegment{PausedAt: sleptAt, ResumedAt: wokeAt})
+		pt.persist()
+	}
+	pt.emit("timer:wake", int(wokeAt.Sub(sleptAt).Seconds()))
+}
+
+// readIdle asks the idle detector how long the user has been idle, without holding the
+// mutex. It reports false when the running phase does not watch for idleness or the
+// detector cannot tell, in which case the user counts as present.
+func (pt *PomodoroTimer) readIdle() (time.Duration, bool) {
+	pt.mutex.RLock()
+	watching := pt.state.IsRunning && pt.focusing() && pt.settings.IdlePauseMins > 0
+	pt.mutex.RUnlock()
+	if !watching {
+		return 0, false
+	}
+
+	idle, err := pt.idle.IdleTime()
+	if err != nil {
+		return 0, false
+	}
+	return idle, true
+}
+
+// watchIdle pauses the running work phase or stopwatch once the user has been idle for
+// the configured time, from the moment they went idle, and asks them what to do with that
+// time when they come back. The caller holds the mutex.
+func (pt *PomodoroTimer) watchIdle(now time.Time, idle time.Duration) {
+	threshold := time.Duration(pt.settings.IdlePauseMins) * time.Minute
+	if threshold <= 0 || pt.state.IdleReturnedAt != nil || (pt.state.IsPaused && !pt.state.IdlePaused) {
+		return
+	}
+
+	switch {
+	case !pt.state.IdlePaused && idle >= threshold:
+		// Time already spent paused or on another task is left as it is
+		since := later(now.Add(-idle), pt.state.StartedAt)
+		if n := len(pt.state.Pauses); n > 0 {
+			since = later(since, pt.state.Pauses[n-1].ResumedAt)
+		}
+		if n := len(pt.state.TaskSwitches); n > 0 {
+			since = later(since, pt.state.TaskSwitches[n-1].SwitchedAt)
+		}
+		pt.state.IsPaused = true
+		pt.state.IdlePaused = true
+		pt.state.PausedAt = &since
+		pt.persist()
+		pt.emit("timer:idle", *pt.state)
+	case pt.state.IdlePaused && idle < threshold:
+		returned := now.Add(-idle)
+		pt.state.IdleReturnedAt = &returned
+		pt.persist()
+		pt.emit("timer:idle-return", *pt.state)
+	}
+}
+
+// later returns the later of two times
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// focusing reports whether the timer measures focus time, in a work phase or as a stopwatch
+func (pt *PomodoroTimer) focusing() bool {
+	return pt.state.Mode == TimerModeStopwatch || pt.state.Phase == PomodoroPhaseWork
+}
+
+// elapsed returns the time the running phase has run at now. Time spent paused does not
+// count towards the phase.
+func (pt *PomodoroTimer) elapsed(now time.Time) time.Duration {
+	paused := time.Duration(pt.state.PausedSeconds) * time.Second
+	if pt.state.PausedAt != nil {
+		paused += now.Sub(*pt.state.PausedAt)
+	}
+	return now.Sub(pt.state.StartedAt) - paused
+}
+
+// remaining returns the time left in the running phase at now
+func (pt *PomodoroTimer) remaining(now time.Time) time.Duration {
+	return time.Duration(pt.state.Duration)*time.Second - pt.elapsed(now)
+}
+
+// secondsLeft rounds a remaining time up to whole seconds, so that a phase shows 0 only
+// once it is over
+func secondsLeft(remaining time.Duration) int {
+	return int(math.Ceil(remaining.Seconds()))
+}
+
+// complete handles the end of a phase at completedAt and moves on to the next one,
+// starting it right away when the settings ask for it. The caller holds the mutex.
+func (pt *PomodoroTimer) complete(completedAt time.Time) {
 	pt.ticker.Stop()
 	pt.state.IsRunning = false
 	pt.state.IsPaused = false
 
-	// Emit timer complete event
+	finished := pt.state.Phase
+	if finished == PomodoroPhaseWork {
+		// Only work phases count as focus time; breaks are not recorded
+		sessions, err := pt.recordSession(pt.state.Duration/60, completedAt, SessionOutcomeCompleted, "")
+		if err != nil {
+			log.Printf("failed to record pomodoro session: %v", err)
+		}
+		pt.state.CycleCount++
+		pt.emit("timer:complete", sessions)
+	}
+
+	autoStart := pt.advance()
+	pt.emit("timer:phase", PomodoroPhaseChange{From: finished, To: pt.state.Phase, State: *pt.state})
+
+	if autoStart {
+		pt.begin(pt.settings.phaseMinutes(pt.state.Phase))
+	} else {
+		pt.persist()
+	}
+}
+
+// advance moves the cycle to the phase following the current one and reports whether
+// the settings want it started right away. The caller holds the mutex.
+func (pt *PomodoroTimer) advance() bool {
+	if pt.state.Phase != PomodoroPhaseWork {
+		if pt.state.Phase == PomodoroPhaseLongBreak {
+			pt.state.CycleCount = 0
+		}
+		pt.setPhase(PomodoroPhaseWork)
+		return pt.settings.AutoStartWork
+	}
+
+	if pt.state.CycleCount >= pt.settings.LongBreakEvery {
+		pt.setPhase(PomodoroPhaseLongBreak)
+	} else {
+		pt.setPhase(PomodoroPhaseShortBreak)
+	}
+	return pt.settings.AutoStartBreaks
+}
+
+// setPhase makes phase the upcoming phase of a stopped timer. The caller holds the mutex.
+func (pt *PomodoroTimer) setPhase(phase string) {
+	pt.state.Phase = phase
+	pt.state.Duration = pt.settings.phaseMinutes(phase) * 60
+	pt.state.TimeRemaining = 0
+	pt.state.Elapsed = 0
+	pt.clearPauses()
+}
+
+// clearPauses forgets the pauses, interruptions and task switches of the current phase. The
+// caller holds the mutex.
+func (pt *PomodoroTimer) clearPauses() {
+	pt.state.PausedAt = nil
+	pt.state.PausedSeconds = 0
+	pt.state.Pauses = nil
+	pt.state.Interruptions = nil
+	pt.state.TaskSwitches = nil
+}
+
+// emit sends an event to the frontend, if there is one
+func (pt *PomodoroTimer) emit(event string, data ...interface{}) {
 	if pt.app.ctx != nil {
-		runtime.EventsEmit(pt.app.ctx, "timer:complete")
+		runtime.EventsEmit(pt.app.ctx, event, data...)
 	}
 }
 
@@ -94,8 +376,11 @@ func (pt *PomodoroTimer) Pause() {
 	pt.mutex.Lock()
 	defer pt.mutex.Unlock()
 
-	if pt.state.IsRunning {
+	if pt.state.IsRunning && !pt.state.IsPaused {
+		now := pt.now()
 		pt.state.IsPaused = true
+		pt.state.PausedAt = &now
+		pt.persist()
 	}
 }
 
@@ -104,23 +389,377 @@ func (pt *PomodoroTimer) Resume() {
 	pt.mutex.Lock()
 	defer pt.mutex.Unlock()
 
-	if pt.state.IsRunning {
+	if pt.state.IsRunning && pt.state.IsPaused {
 		pt.state.IsPaused = false
+		pt.endPause(pt.now())
+		pt.persist()
+	}
+}
+
+// endPause ends the pause in progress, if any, at now and keeps it as a pause segment. The
+// caller holds the mutex.
+func (pt *PomodoroTimer) endPause(now time.Time) {
+	if pt.state.PausedAt == nil {
+		return
+	}
+	pt.state.PausedSeconds += int(now.Sub(*pt.state.PausedAt).Seconds())
+	pt.state.Pauses = append(pt.state.Pauses, PauseSegment{PausedAt: *pt.state.PausedAt, ResumedAt: now})
+	pt.state.PausedAt = nil
+	pt.state.IdlePaused = false
+	pt.state.IdleReturnedAt = nil
+}
+
+// ResolveIdle settles the time the timer was paused while the user was away, then carries
+// on. The time can be kept as focus time, discarded like a pause, or reassigned to the
+// task with taskID. Time since the user came back counts as focus time in every case.
+func (pt *PomodoroTimer) ResolveIdle(action string, taskID *int64) error {
+	if err := validateIdleAction(action); err != nil {
+		return err
+	}
+
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	if !pt.state.IsRunning || !pt.state.IdlePaused {
+		return fmt.Errorf("the timer is not paused for idleness")
 	}
+
+	returned := pt.now()
+	if pt.state.IdleReturnedAt != nil {
+		returned = *pt.state.IdleReturnedAt
+	}
+	since := *pt.state.PausedAt
+
+	switch action {
+	case IdleActionDiscard:
+		pt.endPause(returned)
+	case IdleActionKeep, IdleActionReassign:
+		if action == IdleActionReassign && !sameOptionalID(taskID, pt.state.TaskID) {
+			pt.state.TaskSwitches = append(pt.state.TaskSwitches,
+				TaskSwitch{TaskID: pt.state.TaskID, SwitchedAt: since},
+				TaskSwitch{TaskID: taskID, SwitchedAt: returned})
+		}
+		pt.state.PausedAt = nil
+		pt.state.IdlePaused = false
+		pt.state.IdleReturnedAt = nil
+	}
+	pt.state.IsPaused = false
+	pt.persist()
+	return nil
 }
 
-// Stop stops the timer
-func (pt *PomodoroTimer) Stop() {
+// LogInterruption notes an interruption of the running work phase or stopwatch, to be
+// recorded with its session
+func (pt *PomodoroTimer) LogInterruption(kind, note string) error {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	if !pt.state.IsRunning || !pt.focusing() {
+		return fmt.Errorf("no pomodoro is running")
+	}
+	pt.state.Interruptions = append(pt.state.Interruptions, Interruption{
+		ID:         GenerateID(),
+		UserID:     pt.userID,
+		Kind:       kind,
+		Note:       note,
+		OccurredAt: pt.now(),
+	})
+	pt.persist()
+	return nil
+}
+
+// ExtendPhase adds minutes to the running phase of the cycle
+func (pt *PomodoroTimer) ExtendPhase(minutes int) error {
+	if minutes <= 0 {
+		return fmt.Errorf("extension must be a positive number of minutes")
+	}
+
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	if !pt.state.IsRunning || pt.state.Mode != TimerModePomodoro {
+		return fmt.Errorf("no pomodoro is running")
+	}
+	pt.state.Duration += minutes * 60
+	pt.state.TimeRemaining = secondsLeft(pt.remaining(pt.now()))
+	pt.persist()
+	pt.emit("timer:tick", *pt.state)
+	return nil
+}
+
+// SkipPhase moves the cycle on to the next phase without waiting for the current one to
+// end, starting it right away when the settings ask for it. A running work phase is
+// recorded as abandoned, as if stopped, and does not count towards the cycle.
+func (pt *PomodoroTimer) SkipPhase() error {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	if pt.state.IsRunning && pt.state.Mode != TimerModePomodoro {
+		return fmt.Errorf("the stopwatch has no phases to skip")
+	}
+
+	skipped := pt.state.Phase
+	wasRunning := pt.state.IsRunning
+	if wasRunning {
+		now := pt.now()
+		pt.halt(now)
+		if skipped == PomodoroPhaseWork {
+			pt.recordStopped(now, SessionOutcomeAbandoned, "")
+		}
+	}
+
+	autoStart := pt.advance()
+	pt.emit("timer:phase", PomodoroPhaseChange{From: skipped, To: pt.state.Phase, State: *pt.state})
+
+	if wasRunning && autoStart {
+		pt.begin(pt.settings.phaseMinutes(pt.state.Phase))
+	} else {
+		pt.persist()
+	}
+	return nil
+}
+
+// SwitchTask moves the running work phase or stopwatch on to another task. The session is
+// recorded in one part per task, so each gets the time spent on it.
+func (pt *PomodoroTimer) SwitchTask(taskID *int64) error {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	if !pt.state.IsRunning || !pt.focusing() {
+		return fmt.Errorf("no pomodoro is running")
+	}
+	if pt.state.IdlePaused {
+		return fmt.Errorf("the idle time must be settled first")
+	}
+	if sameOptionalID(pt.state.TaskID, taskID) {
+		return nil
+	}
+	pt.state.TaskSwitches = append(pt.state.TaskSwitches, TaskSwitch{TaskID: pt.state.TaskID, SwitchedAt: pt.now()})
+	pt.state.TaskID = taskID
+	pt.persist()
+	return nil
+}
+
+// Stop stops the timer. Stopping a work phase early records the focus time so far as an
+// abandoned session, with an optional reason, and stopping the stopwatch records the time
+// it ran. Stopping a break ends it, so the next phase is work again.
+func (pt *PomodoroTimer) Stop(reason string) {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	if !pt.state.IsRunning {
+		return
+	}
+
+	now := pt.now()
+	pt.halt(now)
+
+	switch {
+	case pt.state.Mode == TimerModeStopwatch:
+		pt.recordStopped(now, SessionOutcomeCompleted, "")
+		pt.state.Mode = TimerModePomodoro
+		pt.setPhase(pt.state.Phase)
+	case pt.state.Phase == PomodoroPhaseWork:
+		pt.recordStopped(now, SessionOutcomeAbandoned, reason)
+		pt.setPhase(PomodoroPhaseWork)
+	default:
+		previous := pt.state.Phase
+		pt.advance()
+		pt.emit("timer:phase", PomodoroPhaseChange{From: previous, To: pt.state.Phase, State: *pt.state})
+	}
+	pt.persist()
+}
+
+// halt stops the running phase at now, ending the pause in progress if any. The caller
+// holds the mutex.
+func (pt *PomodoroTimer) halt(now time.Time) {
+	pt.stopTicking()
+	pt.endPause(now)
+	pt.state.IsRunning = false
+	pt.state.IsPaused = false
+	pt.state.TimeRemaining = 0
+}
+
+// recordStopped records the focus time of a work phase or stopwatch run stopped at now,
+// counted in whole minutes, unless it is shorter than the configured minimum. The caller
+// holds the mutex.
+func (pt *PomodoroTimer) recordStopped(now time.Time, outcome, reason string) {
+	minutes := int(pt.elapsed(now).Round(time.Minute) / time.Minute)
+	if minutes < max(1, pt.settings.MinRecordMins) {
+		return
+	}
+	sessions, err := pt.recordSession(minutes, now, outcome, reason)
+	if err != nil {
+		log.Printf("failed to record stopped session: %v", err)
+		return
+	}
+	pt.emit("timer:stopped", sessions)
+}
+
+// ResetCycle stops the timer and starts over with a work phase and an empty cycle
+func (pt *PomodoroTimer) ResetCycle() {
+	pt.Stop("")
+
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	pt.state.CycleCount = 0
+	pt.setPhase(PomodoroPhaseWork)
+	pt.persist()
+}
+
+// Close stops the timer loop when the app quits, without ending the phase, so that
+// Restore picks it up when its user logs in again
+func (pt *PomodoroTimer) Close() {
 	pt.mutex.Lock()
 	defer pt.mutex.Unlock()
 
 	if pt.state.IsRunning {
-		pt.ticker.Stop()
-		pt.stopChan <- true
-		pt.state.IsRunning = false
-		pt.state.IsPaused = false
-		pt.state.TimeRemaining = 0
+		pt.stopTicking()
+	}
+}
+
+// Release puts the timer of the user logging out aside, without ending the phase, so
+// that Restore picks it up when they log in again. Until then the timer is blank, so the
+// next user does not see it.
+func (pt *PomodoroTimer) Release() {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	if pt.state.IsRunning {
+		pt.stopTicking()
+	}
+	pt.userID = 0
+	pt.settings = defaultPomodoroSettings
+	pt.state = newTimerState()
+}
+
+// savedTimer is the timer state kept in the settings table between app runs
+type savedTimer struct {
+	UserID int64      `json:"user_id"`
+	State  TimerState `json:"state"`
+}
+
+// timerStateKey returns the settings key of the saved timer of a user
+func timerStateKey(userID int64) string {
+	return fmt.Sprintf("timer_state:%d", userID)
+}
+
+// persist saves the timer state of its user so it survives a restart. The caller holds
+// the mutex.
+func (pt *PomodoroTimer) persist() {
+	if pt.app.storage == nil || pt.userID == 0 {
+		return
+	}
+	value, err := json.Marshal(savedTimer{UserID: pt.userID, State: *pt.state})
+	if err == nil {
+		err = pt.app.storage.SaveSetting(timerStateKey(pt.userID), string(value))
+	}
+	if err != nil {
+		log.Printf("failed to save timer state: %v", err)
+	}
+}
+
+// loadSavedTimer returns the timer saved for a user, or nil if there is none
+func (pt *PomodoroTimer) loadSavedTimer(userID int64) (*savedTimer, error) {
+	value, err := pt.app.storage.GetSetting(timerStateKey(userID))
+	if err != nil || value == "" {
+		return nil, err
+	}
+	var saved savedTimer
+	if err := json.Unmarshal([]byte(value), &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse saved timer: %v", err)
+	}
+	return &saved, nil
+}
+
+// Restore loads the settings of a user logging in and the timer they left, if any. A
+// phase that was running continues with the time it had left, not counting the time spent
+// paused. A work phase that ran out in the meantime is recorded as a finished session.
+func (pt *PomodoroTimer) Restore(userID int64) error {
+	settings, err := pt.app.pomodoroSettings(userID)
+	if err != nil {
+		return err
+	}
+	saved, err := pt.loadSavedTimer(userID)
+	if err != nil {
+		return err
+	}
+
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	pt.userID = userID
+	pt.settings = *settings
+	if saved == nil {
+		pt.state.LongBreakEvery = settings.LongBreakEvery
+		return nil
+	}
+	*pt.state = saved.State
+	pt.state.LongBreakEvery = settings.LongBreakEvery
+	if pt.state.Mode == "" {
+		pt.state.Mode = TimerModePomodoro // Saved before the stopwatch existed
+	}
+	if !pt.state.IsRunning {
+		return nil
+	}
+
+	now := pt.now()
+	if pt.state.Mode == TimerModeStopwatch {
+		pt.state.Elapsed = int(pt.elapsed(now).Seconds())
+		pt.startTicking()
+		pt.persist()
+		return nil
+	}
+	remaining := pt.remaining(now)
+	if remaining > 0 {
+		pt.state.TimeRemaining = secondsLeft(remaining)
+		pt.startTicking()
+		pt.persist()
+		return nil
+	}
+
+	// The phase ended while the app was closed
+	pt.state.IsRunning = false
+	pt.state.IsPaused = false
+	if pt.state.Phase == PomodoroPhaseWork {
+		if _, err := pt.recordSession(pt.state.Duration/60, now.Add(remaining), SessionOutcomeCompleted, ""); err != nil {
+			return err
+		}
+		pt.state.CycleCount++
+	}
+	pt.advance()
+	pt.persist()
+	return nil
+}
+
+// recordSession saves the work phase or stopwatch run that just ended as a Pomodoro
+// session of the given minutes, in one part per task it was switched between, and returns
+// the sessions saved. The caller holds the mutex.
+func (pt *PomodoroTimer) recordSession(minutes int, completedAt time.Time, outcome, reason string) ([]PomodoroSession, error) {
+	session := &PomodoroSession{
+		ID:            GenerateID(),
+		UserID:        pt.userID,
+		TaskID:        pt.state.TaskID,
+		Mode:          pt.state.Mode,
+		Outcome:       outcome,
+		Reason:        reason,
+		Duration:      minutes,
+		StartedAt:     pt.state.StartedAt,
+		CompletedAt:   completedAt,
+		PausedSeconds: pt.state.PausedSeconds,
+		Pauses:        pt.state.Pauses,
+	}
+	for _, interruption := range pt.state.Interruptions {
+		interruption.SessionID = session.ID
+		session.Interruptions = append(session.Interruptions, interruption)
+	}
+	sessions := splitByTask(*session, pt.state.TaskSwitches)
+	if err := pt.app.storage.CreatePomodoroSessions(sessions); err != nil {
+		return nil, err
 	}
+	pt.app.cache.Delete(fmt.Sprintf("sessions:%d", pt.userID))
+	return sessions, nil
 }
 
 // GetState returns the current timer state
@@ -0,0 +1,348 @@
+package backend
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClock is a clock the tests move by hand. The timer loop reads it too, so it is
+// guarded by a mutex.
+type testClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestApp returns an app with a fresh database and a logged-in user
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	app := NewApp()
+	app.Startup(nil)
+	if app.storage == nil {
+		t.Fatal("storage was not initialized")
+	}
+	t.Cleanup(func() { app.Shutdown(nil) })
+
+	if err := app.Register("tester", "tester@example.com", "secret"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, err := app.Login("tester", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return app
+}
+
+// newTestTimer replaces the timer of app with one that reads the time from clock and the
+// idle time from idle
+func newTestTimer(app *App, clock *testClock, idle IdleDetector) *PomodoroTimer {
+	pt := newPomodoroTimer(app, clock.Now, idle)
+	app.pomodoroTimer = pt
+	return pt
+}
+
+// step moves the clock on by d and ticks the running phase after reading the idle time,
+// as the timer loop does, reporting whether it is still running
+func step(pt *PomodoroTimer, clock *testClock, d time.Duration) bool {
+	clock.Advance(d)
+	idle, idleKnown := pt.readIdle()
+	pt.mutex.RLock()
+	stopChan := pt.stopChan
+	pt.mutex.RUnlock()
+	return pt.tick(stopChan, idle, idleKnown)
+}
+
+// run moves the clock on by d one second at a time, ticking the running phase as the
+// timer loop would, and reports whether it is still running
+func run(pt *PomodoroTimer, clock *testClock, d time.Duration) bool {
+	for ; d > 0; d -= time.Second {
+		if !step(pt, clock, min(d, time.Second)) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTimerFollowsTheClockWhenTicksAreLate(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	pt := newTestTimer(app, clock, &FakeIdleDetector{})
+
+	if err := pt.Start(app.currentUser.ID, 25, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	// Ticks arriving every 1.3s would lose 30s over 100 ticks if each counted as a second
+	for i := 0; i < 100; i++ {
+		if !step(pt, clock, 1300*time.Millisecond) {
+			t.Fatalf("phase ended after %d ticks", i+1)
+		}
+	}
+
+	state := pt.GetState()
+	if state.Elapsed != 130 {
+		t.Errorf("Elapsed = %d, want 130", state.Elapsed)
+	}
+	if state.TimeRemaining != 25*60-130 {
+		t.Errorf("TimeRemaining = %d, want %d", state.TimeRemaining, 25*60-130)
+	}
+
+	// A fraction of a second left still shows as a second
+	step(pt, clock, 300*time.Millisecond)
+	if got := pt.GetState().TimeRemaining; got != 25*60-130 {
+		t.Errorf("TimeRemaining = %d, want %d", got, 25*60-130)
+	}
+}
+
+func TestTimerCountsSuspendAsPause(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	pt := newTestTimer(app, clock, &FakeIdleDetector{})
+
+	if err := pt.Start(app.currentUser.ID, 25, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	startedAt := clock.Now()
+	step(pt, clock, 10*time.Second)
+
+	// A gap up to suspendGap is a late tick, not a suspend
+	step(pt, clock, suspendGap)
+	if state := pt.GetState(); state.PausedSeconds != 0 || len(state.Pauses) != 0 {
+		t.Fatalf("late tick counted as a pause: %d seconds, %d pauses", state.PausedSeconds, len(state.Pauses))
+	}
+
+	step(pt, clock, time.Hour)
+	state := pt.GetState()
+	if state.PausedSeconds != 3600 {
+		t.Errorf("PausedSeconds = %d, want 3600", state.PausedSeconds)
+	}
+	if len(state.Pauses) != 1 {
+		t.Fatalf("got %d pauses, want 1", len(state.Pauses))
+	}
+	sleptAt := startedAt.Add(10*time.Second + suspendGap)
+	if pause := state.Pauses[0]; !pause.PausedAt.Equal(sleptAt) || !pause.ResumedAt.Equal(sleptAt.Add(time.Hour)) {
+		t.Errorf("pause = %v to %v, want %v to %v", pause.PausedAt, pause.ResumedAt, sleptAt, sleptAt.Add(time.Hour))
+	}
+	if want := 25*60 - 25; state.TimeRemaining != want {
+		t.Errorf("TimeRemaining = %d, want %d", state.TimeRemaining, want)
+	}
+	if state.IsPaused {
+		t.Error("timer stayed paused after waking up")
+	}
+}
+
+func TestTimerKeepsBreaksRunningThroughSuspend(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	pt := newTestTimer(app, clock, &FakeIdleDetector{})
+
+	pt.mutex.Lock()
+	pt.setPhase(PomodoroPhaseShortBreak)
+	pt.mutex.Unlock()
+	if err := pt.Start(app.currentUser.ID, 0, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	run(pt, clock, time.Minute)
+	if step(pt, clock, 10*time.Minute) {
+		t.Fatal("break did not end")
+	}
+	if phase := pt.GetState().Phase; phase != PomodoroPhaseWork {
+		t.Errorf("phase = %s, want %s", phase, PomodoroPhaseWork)
+	}
+}
+
+func TestTimerCompletesAtTheDeadline(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	pt := newTestTimer(app, clock, &FakeIdleDetector{})
+
+	task, err := app.CreateTask("Write report", "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if err := pt.Start(app.currentUser.ID, 25, &task.ID); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	deadline := clock.Now().Add(25 * time.Minute)
+
+	if !run(pt, clock, 25*time.Minute-time.Second) {
+		t.Fatal("phase ended a second early")
+	}
+	if got := pt.GetState().TimeRemaining; got != 1 {
+		t.Errorf("TimeRemaining = %d, want 1", got)
+	}
+	if step(pt, clock, time.Second) {
+		t.Fatal("phase still running at its deadline")
+	}
+
+	state := pt.GetState()
+	if state.IsRunning || state.Phase != PomodoroPhaseShortBreak || state.CycleCount != 1 {
+		t.Errorf("running = %v, phase = %s, cycle = %d; want a stopped short break after 1 pomodoro",
+			state.IsRunning, state.Phase, state.CycleCount)
+	}
+	sessions, err := app.storage.getAllSessionsForUser(app.currentUser.ID)
+	if err != nil {
+		t.Fatalf("getAllSessionsForUser: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(sessions))
+	}
+	session := sessions[0]
+	if session.Duration != 25 || session.Outcome != SessionOutcomeCompleted || !session.CompletedAt.Equal(deadline) {
+		t.Errorf("session = %d min %s at %v, want 25 min %s at %v",
+			session.Duration, session.Outcome, session.CompletedAt, SessionOutcomeCompleted, deadline)
+	}
+	if session.TaskID == nil || *session.TaskID != task.ID {
+		t.Errorf("session task = %v, want %d", session.TaskID, task.ID)
+	}
+}
+
+func TestTimerCompletesAtTheDeadlineWhenTheTickIsLate(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	pt := newTestTimer(app, clock, &FakeIdleDetector{})
+
+	if err := pt.Start(app.currentUser.ID, 25, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	deadline := clock.Now().Add(25 * time.Minute)
+	run(pt, clock, 25*time.Minute-time.Second)
+	if step(pt, clock, 5*time.Second) {
+		t.Fatal("phase still running past its deadline")
+	}
+
+	sessions, err := app.storage.getAllSessionsForUser(app.currentUser.ID)
+	if err != nil {
+		t.Fatalf("getAllSessionsForUser: %v", err)
+	}
+	if len(sessions) != 1 || !sessions[0].CompletedAt.Equal(deadline) {
+		t.Fatalf("sessions = %+v, want one completed at %v", sessions, deadline)
+	}
+}
+
+func TestRestoreResumesARunningPhase(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	pt := newTestTimer(app, clock, &FakeIdleDetector{})
+
+	task, err := app.CreateTask("Write report", "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if err := pt.Start(app.currentUser.ID, 25, &task.ID); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	run(pt, clock, 3*time.Minute)
+	pt.Pause()
+	run(pt, clock, time.Minute)
+	pt.Resume()
+	run(pt, clock, 2*time.Minute)
+	pt.Close()
+
+	// The app stays closed for 2 minutes
+	clock.Advance(2 * time.Minute)
+	restored := newTestTimer(app, clock, &FakeIdleDetector{})
+	if err := restored.Restore(app.currentUser.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	state := restored.GetState()
+	if !state.IsRunning || state.IsPaused {
+		t.Fatalf("running = %v, paused = %v; want a running phase", state.IsRunning, state.IsPaused)
+	}
+	if want := 25*60 - 7*60; state.TimeRemaining != want {
+		t.Errorf("TimeRemaining = %d, want %d", state.TimeRemaining, want)
+	}
+	if state.PausedSeconds != 60 || len(state.Pauses) != 1 {
+		t.Errorf("paused %d seconds in %d pauses, want 60 in 1", state.PausedSeconds, len(state.Pauses))
+	}
+	if state.TaskID == nil || *state.TaskID != task.ID {
+		t.Errorf("TaskID = %v, want %d", state.TaskID, task.ID)
+	}
+
+	// The restored phase keeps ticking
+	if !step(restored, clock, 10*time.Second) {
+		t.Fatal("restored phase stopped ticking")
+	}
+	if want := 25*60 - 7*60 - 10; restored.GetState().TimeRemaining != want {
+		t.Errorf("TimeRemaining = %d, want %d", restored.GetState().TimeRemaining, want)
+	}
+}
+
+func TestTimerCanBeStoppedAfterClose(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	pt := newTestTimer(app, clock, &FakeIdleDetector{})
+
+	if err := pt.Start(app.currentUser.ID, 25, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	run(pt, clock, 5*time.Minute)
+	pt.Close()
+
+	pt.Stop("")
+	pt.Release()
+	if pt.GetState().IsRunning {
+		t.Error("timer still running after Stop")
+	}
+}
+
+func TestRestoreRecordsAPhaseThatEndedWhileClosed(t *testing.T) {
+	app := newTestApp(t)
+	clock := newTestClock()
+	pt := newTestTimer(app, clock, &FakeIdleDetector{})
+
+	if err := pt.Start(app.currentUser.ID, 25, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	deadline := clock.Now().Add(25 * time.Minute)
+	run(pt, clock, 5*time.Minute)
+	pt.Close()
+
+	clock.Advance(time.Hour)
+	restored := newTestTimer(app, clock, &FakeIdleDetector{})
+	if err := restored.Restore(app.currentUser.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	state := restored.GetState()
+	if state.IsRunning || state.Phase != PomodoroPhaseShortBreak || state.CycleCount != 1 {
+		t.Errorf("running = %v, phase = %s, cycle = %d; want a stopped short break after 1 pomodoro",
+			state.IsRunning, state.Phase, state.CycleCount)
+	}
+	sessions, err := app.storage.getAllSessionsForUser(app.currentUser.ID)
+	if err != nil {
+		t.Fatalf("getAllSessionsForUser: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(sessions))
+	}
+	if session := sessions[0]; session.Duration != 25 || !session.CompletedAt.Equal(deadline) {
+		t.Errorf("session = %d min at %v, want 25 min at %v", session.Duration, session.CompletedAt, deadline)
+	}
+
+	// The session is recorded once, not again on the next start
+	again := newTestTimer(app, clock, &FakeIdleDetector{})
+	if err := again.Restore(app.currentUser.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if sessions, _ := app.storage.getAllSessionsForUser(app.currentUser.ID); len(sessions) != 1 {
+		t.Errorf("got %d sessions after restarting again, want 1", len(sessions))
+	}
+}
@@ -0,0 +1,24 @@
+package backend
+
+import (
+	"time"
+)
+
+// Project represents a client or product that tasks belong to
+type Project struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `json:"name"`
+	Color      string     `json:"color"`
+	Archived   bool       `json:"archived"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+}
+
+// ProjectReport represents the time spent on a project within a report range
+type ProjectReport struct {
+	ProjectID int64  `json:"project_id"`
+	Name      string `json:"name"`
+	Sessions  int    `json:"sessions"`
+	Minutes   int    `json:"minutes"`
+}
@@ -0,0 +1,263 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies supported from RFC 5545
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+// RecurrenceDay is a BYDAY entry. Ordinal is only used with MONTHLY rules:
+// 1 is the first such weekday of the month, -1 the last, 0 every one of them.
+type RecurrenceDay struct {
+	Weekday time.Weekday
+	Ordinal int
+}
+
+// RecurrenceRule is the subset of an RFC 5545 RRULE that tasks can repeat on:
+// FREQ (DAILY, WEEKLY or MONTHLY), INTERVAL, BYDAY and either UNTIL or COUNT
+type RecurrenceRule struct {
+	Freq     string
+	Interval int
+	ByDay    []RecurrenceDay
+	Until    *time.Time // Last allowed occurrence date, inclusive
+	Count    int        // Total number of occurrences, 0 for no limit
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// ParseRecurrenceRule parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE".
+// A leading "RRULE:" is accepted.
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	rule := &RecurrenceRule{Interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence rule part: %s", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+			if rule.Freq != FreqDaily && rule.Freq != FreqWeekly && rule.Freq != FreqMonthly {
+				return nil, fmt.Errorf("unsupported recurrence frequency: %s", val)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid recurrence interval: %s", val)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(val), ",") {
+				if len(day) < 2 {
+					return nil, fmt.Errorf("invalid recurrence day: %s", day)
+				}
+				weekday, ok := rruleWeekdays[day[len(day)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid recurrence day: %s", day)
+				}
+				entry := RecurrenceDay{Weekday: weekday}
+				if prefix := day[:len(day)-2]; prefix != "" {
+					ordinal, err := strconv.Atoi(prefix)
+					if err != nil || ordinal == 0 || ordinal < -5 || ordinal > 5 {
+						return nil, fmt.Errorf("invalid recurrence day: %s", day)
+					}
+					entry.Ordinal = ordinal
+				}
+				rule.ByDay = append(rule.ByDay, entry)
+			}
+		case "UNTIL":
+			until, err := parseRecurrenceUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid recurrence count: %s", val)
+			}
+			rule.Count = count
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part: %s", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("recurrence rule is missing FREQ")
+	}
+	if rule.Until != nil && rule.Count > 0 {
+		return nil, fmt.Errorf("recurrence rule cannot have both UNTIL and COUNT")
+	}
+	for _, day := range rule.ByDay {
+		if day.Ordinal != 0 && rule.Freq != FreqMonthly {
+			return nil, fmt.Errorf("numbered BYDAY is only supported with FREQ=MONTHLY")
+		}
+	}
+	// Steps of whole weeks always land on the weekday they started from
+	if rule.Freq == FreqDaily && rule.Interval%7 == 0 && len(rule.ByDay) > 0 {
+		return nil, fmt.Errorf("BYDAY is not supported with FREQ=DAILY and an INTERVAL of whole weeks")
+	}
+
+	return rule, nil
+}
+
+// parseRecurrenceUntil parses an UNTIL value in date (20261231) or date-time (20261231T235959Z) form
+func parseRecurrenceUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102", "20060102T150405Z", "20060102T150405"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return dateOnly(t), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid recurrence UNTIL: %s", value)
+}
+
+// Next returns the first occurrence date after the given one, which must itself be an
+// occurrence. occurrence is the 1-based index of the given one within the series.
+// It returns false once the series has ended through UNTIL or COUNT.
+func (r *RecurrenceRule) Next(current time.Time, occurrence int) (time.Time, bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	current = dateOnly(current)
+	var next time.Time
+	switch r.Freq {
+	case FreqDaily:
+		next = r.nextDaily(current)
+	case FreqWeekly:
+		next = r.nextWeekly(current)
+	case FreqMonthly:
+		next = r.nextMonthly(current)
+	}
+
+	if next.IsZero() || (r.Until != nil && next.After(*r.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// matchesWeekday reports whether a weekday is allowed by BYDAY, ignoring ordinals
+func (r *RecurrenceRule) matchesWeekday(weekday time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RecurrenceRule) nextDaily(current time.Time) time.Time {
+	// BYDAY limits which days of the INTERVAL-day steps count. INTERVAL is never a multiple
+	// of 7 with BYDAY, so a week of steps goes through every weekday.
+	for i := 1; i <= 7; i++ {
+		next := current.AddDate(0, 0, i*r.Interval)
+		if r.matchesWeekday(next.Weekday()) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+func (r *RecurrenceRule) nextWeekly(current time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		return current.AddDate(0, 0, 7*r.Interval)
+	}
+
+	// Weeks start on Monday (the RFC 5545 default WKST)
+	offset := (int(current.Weekday()) + 6) % 7
+	weekStart := current.AddDate(0, 0, -offset)
+
+	for day := offset + 1; day < 7; day++ {
+		if candidate := weekStart.AddDate(0, 0, day); r.matchesWeekday(candidate.Weekday()) {
+			return candidate
+		}
+	}
+
+	nextWeek := weekStart.AddDate(0, 0, 7*r.Interval)
+	for day := 0; day < 7; day++ {
+		if candidate := nextWeek.AddDate(0, 0, day); r.matchesWeekday(candidate.Weekday()) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+func (r *RecurrenceRule) nextMonthly(current time.Time) time.Time {
+	monthStart := time.Date(current.Year(), current.Month(), 1, 0, 0, 0, 0, current.Location())
+
+	// Months without a matching day (e.g. the 31st) are skipped, as RFC 5545 requires
+	for step := 0; step <= 12*4; step++ {
+		month := monthStart.AddDate(0, step*r.Interval, 0)
+		for _, candidate := range r.monthCandidates(month, current.Day()) {
+			if candidate.After(current) {
+				return candidate
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// monthCandidates lists the occurrence dates within the month starting at monthStart, sorted
+func (r *RecurrenceRule) monthCandidates(monthStart time.Time, dayOfMonth int) []time.Time {
+	daysInMonth := monthStart.AddDate(0, 1, -1).Day()
+
+	if len(r.ByDay) == 0 {
+		if dayOfMonth > daysInMonth {
+			return nil
+		}
+		return []time.Time{monthStart.AddDate(0, 0, dayOfMonth-1)}
+	}
+
+	var candidates []time.Time
+	for _, day := range r.ByDay {
+		var matches []time.Time
+		for d := 0; d < daysInMonth; d++ {
+			if date := monthStart.AddDate(0, 0, d); date.Weekday() == day.Weekday {
+				matches = append(matches, date)
+			}
+		}
+
+		switch {
+		case day.Ordinal == 0:
+			candidates = append(candidates, matches...)
+		case day.Ordinal > 0 && day.Ordinal <= len(matches):
+			candidates = append(candidates, matches[day.Ordinal-1])
+		case day.Ordinal < 0 && -day.Ordinal <= len(matches):
+			candidates = append(candidates, matches[len(matches)+day.Ordinal])
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	return candidates
+}
+
+// dateOnly strips the time of day from a time
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
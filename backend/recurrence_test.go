@@ -0,0 +1,75 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceRuleRejects(t *testing.T) {
+	tests := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;COUNT=3;UNTIL=20261231",
+		"FREQ=DAILY;INTERVAL=7;BYDAY=MO",
+		"FREQ=DAILY;INTERVAL=14;BYDAY=MO,FR",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ",
+	}
+	for _, value := range tests {
+		if _, err := ParseRecurrenceRule(value); err == nil {
+			t.Errorf("ParseRecurrenceRule(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	tests := []struct {
+		rule       string
+		current    string
+		occurrence int
+		want       string // Empty when the series has ended
+	}{
+		{"FREQ=DAILY", "2026-01-31", 1, "2026-02-01"},
+		{"RRULE:FREQ=DAILY;INTERVAL=3", "2026-01-30", 1, "2026-02-02"},
+		{"FREQ=DAILY;INTERVAL=7", "2026-01-07", 1, "2026-01-14"},
+		{"FREQ=DAILY;INTERVAL=2;BYDAY=MO,WE,FR", "2026-01-05", 1, "2026-01-07"},
+		{"FREQ=DAILY;INTERVAL=3;BYDAY=MO", "2026-01-05", 1, "2026-01-26"},
+		{"FREQ=WEEKLY", "2026-01-05", 1, "2026-01-12"},
+		{"FREQ=WEEKLY;BYDAY=MO,WE", "2026-01-05", 1, "2026-01-07"},
+		{"FREQ=WEEKLY;BYDAY=MO,WE", "2026-01-07", 1, "2026-01-12"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", "2026-01-07", 1, "2026-01-19"},
+		{"FREQ=MONTHLY", "2026-01-15", 1, "2026-02-15"},
+		{"FREQ=MONTHLY", "2026-01-31", 1, "2026-03-31"},
+		{"FREQ=MONTHLY;INTERVAL=12", "2024-02-29", 1, "2028-02-29"},
+		{"FREQ=MONTHLY;BYDAY=2TU", "2026-01-13", 1, "2026-02-10"},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "2026-01-30", 1, "2026-02-27"},
+		{"FREQ=MONTHLY;BYDAY=1MO,-1FR", "2026-02-02", 1, "2026-02-27"},
+		{"FREQ=DAILY;COUNT=3", "2026-01-05", 2, "2026-01-06"},
+		{"FREQ=DAILY;COUNT=3", "2026-01-06", 3, ""},
+		{"FREQ=DAILY;UNTIL=20260110", "2026-01-09", 1, "2026-01-10"},
+		{"FREQ=DAILY;UNTIL=20260110T235959Z", "2026-01-10", 1, ""},
+	}
+	for _, tt := range tests {
+		rule, err := ParseRecurrenceRule(tt.rule)
+		if err != nil {
+			t.Errorf("ParseRecurrenceRule(%q): %v", tt.rule, err)
+			continue
+		}
+		current, _ := time.ParseInLocation("2006-01-02", tt.current, time.Local)
+
+		next, ok := rule.Next(current, tt.occurrence)
+		got := ""
+		if ok {
+			got = next.Format("2006-01-02")
+		}
+		if got != tt.want {
+			t.Errorf("%s from %s: got %q, want %q", tt.rule, tt.current, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package backend
+
+import (
+	"sort"
+)
+
+// getReportTasks returns all tasks of the current user keyed by ID, for resolving
+// the task of each session in a report. Tasks in the trash are included so that
+// past sessions keep their task, and so are the titles of tasks purged from it.
+func (a *App) getReportTasks() (map[int64]Task, error) {
+	tasks, err := a.storage.getAllTasksForUser(a.currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	purged, err := a.storage.getPurgedTasks(a.currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]Task, len(tasks)+len(purged))
+	for _, task := range purged {
+		purgedAt := task.PurgedAt
+		byID[task.ID] = Task{ID: task.ID, UserID: task.UserID, Title: task.Title, DeletedAt: &purgedAt}
+	}
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	return byID, nil
+}
+
+// taskLineage returns a task ID followed by the IDs of all of its ancestors
+func taskLineage(taskID int64, tasks map[int64]Task) []int64 {
+	lineage := []int64{taskID}
+	seen := map[int64]bool{taskID: true}
+	for {
+		task, ok := tasks[taskID]
+		if !ok || task.ParentID == nil || seen[*task.ParentID] {
+			return lineage
+		}
+		taskID = *task.ParentID
+		seen[taskID] = true
+		lineage = append(lineage, taskID)
+	}
+}
+
+// getProjectReport groups session minutes and counts by the project of their task
+func (a *App) getProjectReport(sessions []PomodoroSession, tasks map[int64]Task) ([]ProjectReport, error) {
+	projects, err := a.storage.GetProjects(a.currentUser.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	byProject := make(map[int64]*ProjectReport)
+	for _, project := range projects {
+		byProject[project.ID] = &ProjectReport{ProjectID: project.ID, Name: project.Name}
+	}
+
+	for _, session := range sessions {
+		if session.TaskID == nil {
+			continue
+		}
+		task, ok := tasks[*session.TaskID]
+		if !ok || task.ProjectID == nil {
+			continue
+		}
+		if entry, ok := byProject[*task.ProjectID]; ok {
+			entry.Sessions++
+			entry.Minutes += session.Duration
+		}
+	}
+
+	result := []ProjectReport{}
+	for _, project := range projects {
+		if entry := byProject[project.ID]; entry.Sessions > 0 {
+			result = append(result, *entry)
+		}
+	}
+	return result, nil
+}
+
+// getTagReport groups session minutes and counts by tag. A session counts towards
+// its own tags and the tags of its task, but only once per tag.
+func (a *App) getTagReport(sessions []PomodoroSession, tasks map[int64]Task) ([]TagReport, error) {
+	tags, err := a.storage.GetTags(a.currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byTag := make(map[int64]*TagReport)
+	for _, tag := range tags {
+		byTag[tag.ID] = &TagReport{TagID: tag.ID, Name: tag.Name}
+	}
+
+	for _, session := range sessions {
+		sessionTags := make(map[int64]bool)
+		for _, tag := range session.Tags {
+			sessionTags[tag.ID] = true
+		}
+		if session.TaskID != nil {
+			for _, tag := range tasks[*session.TaskID].Tags {
+				sessionTags[tag.ID] = true
+			}
+		}
+
+		for tagID := range sessionTags {
+			if entry, ok := byTag[tagID]; ok {
+				entry.Sessions++
+				entry.Minutes += session.Duration
+			}
+		}
+	}
+
+	result := []TagReport{}
+	for _, tag := range tags {
+		if entry := byTag[tag.ID]; entry.Sessions > 0 {
+			result = append(result, *entry)
+		}
+	}
+	return result, nil
+}
+
+// getEstimationReport compares the estimate of each task worked on or completed within
+// the report range with the number of sessions recorded for it and its subtasks over their
+// whole lifetime.
+// Accuracy is the sum of min(estimated, actual) over the sum of max(estimated, actual),
+// so over- and under-runs lower it alike.
+func (a *App) getEstimationReport(sessions []PomodoroSession, tasks map[int64]Task, startDate, endDate string) (*EstimationReport, error) {
+	counts, err := a.storage.CountSessionsByTask(a.currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Sessions on a subtask also count towards every task above it
+	actual := make(map[int64]int)
+	for taskID, count := range counts {
+		for _, id := range taskLineage(taskID, tasks) {
+			actual[id] += count
+		}
+	}
+
+	inRange := make(map[int64]bool)
+	for _, session := range sessions {
+		if session.TaskID != nil {
+			for _, id := range taskLineage(*session.TaskID, tasks) {
+				inRange[id] = true
+			}
+		}
+	}
+	for _, task := range tasks {
+		if task.CompletedAt != nil {
+			date := task.CompletedAt.Format("2006-01-02")
+			if date >= startDate && date <= endDate {
+				inRange[task.ID] = true
+			}
+		}
+	}
+
+	report := &EstimationReport{Tasks: []TaskEstimate{}}
+	matched, spread := 0, 0
+	for _, task := range tasks {
+		if task.EstimatedPomodoros <= 0 || !inRange[task.ID] {
+			continue
+		}
+
+		entry := TaskEstimate{
+			TaskID:    task.ID,
+			Title:     task.Title,
+			Estimated: task.EstimatedPomodoros,
+			Actual:    actual[task.ID],
+		}
+		entry.Overrun = entry.Actual - entry.Estimated
+		report.Tasks = append(report.Tasks, entry)
+
+		report.TotalEstimated += entry.Estimated
+		report.TotalActual += entry.Actual
+		switch {
+		case entry.Overrun > 0:
+			report.OverRuns++
+		case entry.Overrun < 0:
+			report.UnderRuns++
+		default:
+			report.OnTarget++
+		}
+		matched += min(entry.Estimated, entry.Actual)
+		spread += max(entry.Estimated, entry.Actual)
+	}
+
+	sort.Slice(report.Tasks, func(i, j int) bool {
+		if report.Tasks[i].Overrun != report.Tasks[j].Overrun {
+			return report.Tasks[i].Overrun > report.Tasks[j].Overrun
+		}
+		return report.Tasks[i].TaskID < report.Tasks[j].TaskID
+	})
+	if spread > 0 {
+		report.Accuracy = float64(matched) / float64(spread)
+	}
+	return report, nil
+}
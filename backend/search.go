@@ -0,0 +1,65 @@
+package backend
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Search result entity types
+const (
+	SearchTypeTask    = "task"
+	SearchTypeRetro   = "retro"
+	SearchTypeSession = "session"
+)
+
+// searchLimit is the maximum number of results returned per entity type
+const searchLimit = 20
+
+// snippetMarkStart and snippetMarkEnd are private-use characters snippet() puts around
+// matched words, so the text can be escaped before they become <mark> tags
+const (
+	snippetMarkStart = "\uE000"
+	snippetMarkEnd   = "\uE001"
+)
+
+// snippetHTML escapes a snippet from snippet() and turns its match markers into <mark> tags
+func snippetHTML(snippet string) string {
+	return strings.NewReplacer(
+		snippetMarkStart, "<mark>",
+		snippetMarkEnd, "</mark>",
+	).Replace(html.EscapeString(snippet))
+}
+
+// SearchResult is a single full-text search hit. Snippet is HTML: the text is
+// escaped and the matched words are marked with <mark></mark>.
+type SearchResult struct {
+	Type    string  `json:"type"`
+	ID      int64   `json:"id"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+	Date    string  `json:"date,omitempty"` // Retro date or session completion date, YYYY-MM-DD
+	Rank    float64 `json:"rank"`           // bm25 score, lower is a better match
+}
+
+// SearchResults groups search hits by entity type, best matches first
+type SearchResults struct {
+	Tasks    []SearchResult `json:"tasks"`
+	Retros   []SearchResult `json:"retros"`
+	Sessions []SearchResult `json:"sessions"`
+}
+
+// buildSearchQuery turns free text into an FTS5 query that matches documents
+// containing every word, each as a prefix. Quoting the words keeps FTS5
+// operators and punctuation typed by the user from breaking the query.
+func buildSearchQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, `"`+word+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,187 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+// parseSessionTime parses the start or end of a session given as an RFC 3339 timestamp.
+// Session times are stored in local time, like those recorded by the timer, so that they
+// compare correctly.
+func parseSessionTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid session time: %s", value)
+	}
+	return t.Local(), nil
+}
+
+// validateSessionRange checks that a session starts before it ends and has not ended yet
+func validateSessionRange(start, end time.Time) error {
+	if !end.After(start) {
+		return fmt.Errorf("session must end after it starts")
+	}
+	if end.After(time.Now()) {
+		return fmt.Errorf("session cannot end in the future")
+	}
+	return nil
+}
+
+// fitSession clips the pauses and interruptions of a session to its time range and works
+// out its paused time and duration again. Paused time recorded without pause segments is
+// kept, as far as it fits.
+func fitSession(session *PomodoroSession) error {
+	span := session.CompletedAt.Sub(session.StartedAt)
+
+	var pauses []PauseSegment
+	var clipped time.Duration
+	for _, pause := range session.Pauses {
+		length := pause.ResumedAt.Sub(pause.PausedAt)
+		if pause.PausedAt.Before(session.StartedAt) {
+			pause.PausedAt = session.StartedAt
+		}
+		if pause.ResumedAt.After(session.CompletedAt) {
+			pause.ResumedAt = session.CompletedAt
+		}
+		if pause.ResumedAt.After(pause.PausedAt) {
+			pauses = append(pauses, pause)
+			clipped += length - pause.ResumedAt.Sub(pause.PausedAt)
+		} else {
+			clipped += length
+		}
+	}
+	session.Pauses = pauses
+	session.PausedSeconds = max(0, min(session.PausedSeconds-int(clipped.Seconds()), int(span.Seconds())))
+
+	var interruptions []Interruption
+	for _, interruption := range session.Interruptions {
+		if !interruption.OccurredAt.Before(session.StartedAt) && !interruption.OccurredAt.After(session.CompletedAt) {
+			interruption.SessionID = session.ID
+			interruptions = append(interruptions, interruption)
+		}
+	}
+	session.Interruptions = interruptions
+
+	focus := span - time.Duration(session.PausedSeconds)*time.Second
+	session.Duration = int(focus.Round(time.Minute) / time.Minute)
+	if session.Duration < 1 {
+		return fmt.Errorf("session must have at least a minute of focus time")
+	}
+	return nil
+}
+
+// splitSession cuts a session in two at a time within it. The first part keeps the ID,
+// notes, pauses and interruptions before the cut; the second part is a new session with
+// the rest.
+func splitSession(session PomodoroSession, at time.Time) (*PomodoroSession, *PomodoroSession, error) {
+	if !at.After(session.StartedAt) || !at.Before(session.CompletedAt) {
+		return nil, nil, fmt.Errorf("split time must be within the session")
+	}
+
+	first, second := session, session
+	first.CompletedAt = at
+	second.ID = GenerateID()
+	second.StartedAt = at
+	second.Notes = ""
+	second.Tags = nil
+
+	// The parts cover [start, at) and [at, end], so an interruption at the cut goes with the
+	// second part only
+	first.Interruptions, second.Interruptions = nil, nil
+	for _, interruption := range session.Interruptions {
+		if interruption.OccurredAt.Before(at) {
+			first.Interruptions = append(first.Interruptions, interruption)
+		} else {
+			second.Interruptions = append(second.Interruptions, interruption)
+		}
+	}
+
+	// Both parts start out with every pause segment, which fitSession clips. Paused time
+	// recorded without segments is shared out in proportion to the length of the parts.
+	var segmented time.Duration
+	for _, pause := range session.Pauses {
+		segmented += pause.ResumedAt.Sub(pause.PausedAt)
+	}
+	if loose := session.PausedSeconds - int(segmented.Seconds()); loose > 0 {
+		span := session.CompletedAt.Sub(session.StartedAt)
+		looseFirst := int(float64(loose) * float64(at.Sub(session.StartedAt)) / float64(span))
+		first.PausedSeconds = int(segmented.Seconds()) + looseFirst
+		second.PausedSeconds = int(segmented.Seconds()) + loose - looseFirst
+	}
+
+	if err := fitSession(&first); err != nil {
+		return nil, nil, err
+	}
+	if err := fitSession(&second); err != nil {
+		return nil, nil, err
+	}
+	// Rounded on their own, the parts can come to a minute more than the whole, so the
+	// second part gets what the first leaves of the original duration
+	second.Duration = session.Duration - first.Duration
+	if second.Duration < 1 {
+		return nil, nil, fmt.Errorf("session must have at least a minute of focus time")
+	}
+	return &first, &second, nil
+}
+
+// splitByTask cuts a session recorded by the timer at each task switch, so that each task
+// gets its own part. Time too short to make a part of its own goes with the following task,
+// or with the one before at the end of the session.
+func splitByTask(session PomodoroSession, switches []TaskSwitch) []PomodoroSession {
+	var parts []PomodoroSession
+	rest := session
+	for _, taskSwitch := range switches {
+		rest.TaskID = taskSwitch.TaskID
+		first, second, err := splitSession(rest, taskSwitch.SwitchedAt)
+		if err == nil {
+			parts = append(parts, *first)
+			rest = *second
+			continue
+		}
+		tail := rest
+		tail.StartedAt = taskSwitch.SwitchedAt
+		if fitSession(&tail) != nil {
+			return append(parts, rest)
+		}
+	}
+	rest.TaskID = session.TaskID
+	return append(parts, rest)
+}
+
+// mergeSessions joins two sessions, the earlier one keeping its ID and task. The time
+// between them counts as paused. The result is a pomodoro only if both sessions were.
+func mergeSessions(first, second PomodoroSession) (*PomodoroSession, error) {
+	if second.StartedAt.Before(first.StartedAt) {
+		first, second = second, first
+	}
+	if second.StartedAt.Before(first.CompletedAt) {
+		return nil, fmt.Errorf("sessions overlap")
+	}
+
+	merged := first
+	merged.CompletedAt = second.CompletedAt
+	merged.PausedSeconds = first.PausedSeconds + second.PausedSeconds
+	merged.Pauses = append(append([]PauseSegment{}, first.Pauses...), second.Pauses...)
+	merged.Interruptions = append(append([]Interruption{}, first.Interruptions...), second.Interruptions...)
+	if gap := second.StartedAt.Sub(first.CompletedAt); gap > 0 {
+		merged.Pauses = append(merged.Pauses, PauseSegment{PausedAt: first.CompletedAt, ResumedAt: second.StartedAt})
+		merged.PausedSeconds += int(gap.Seconds())
+	}
+	if merged.TaskID == nil {
+		merged.TaskID = second.TaskID
+	}
+	if second.Notes != "" {
+		if merged.Notes != "" {
+			merged.Notes += "\n"
+		}
+		merged.Notes += second.Notes
+	}
+	if first.Mode != second.Mode {
+		merged.Mode = TimerModeStopwatch
+	}
+
+	if err := fitSession(&merged); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
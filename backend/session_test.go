@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitSessionPutsInterruptionsInOnePart(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local)
+	at := start.Add(10 * time.Minute)
+	end := start.Add(25 * time.Minute)
+	session := PomodoroSession{
+		ID:          1,
+		Duration:    25,
+		StartedAt:   start,
+		CompletedAt: end,
+		Interruptions: []Interruption{
+			{ID: 10, OccurredAt: start},
+			{ID: 11, OccurredAt: at},
+			{ID: 12, OccurredAt: end},
+		},
+	}
+
+	first, second, err := splitSession(session, at)
+	if err != nil {
+		t.Fatalf("splitSession: %v", err)
+	}
+
+	ids := func(session *PomodoroSession) []int64 {
+		var ids []int64
+		for _, interruption := range session.Interruptions {
+			if interruption.SessionID != session.ID {
+				t.Errorf("interruption %d has session %d, want %d", interruption.ID, interruption.SessionID, session.ID)
+			}
+			ids = append(ids, interruption.ID)
+		}
+		return ids
+	}
+	if got := ids(first); len(got) != 1 || got[0] != 10 {
+		t.Errorf("first part has interruptions %v, want [10]", got)
+	}
+	if got := ids(second); len(got) != 2 || got[0] != 11 || got[1] != 12 {
+		t.Errorf("second part has interruptions %v, want [11 12]", got)
+	}
+}
+
+func TestSplitSessionKeepsTheDuration(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local)
+	session := PomodoroSession{
+		ID:          1,
+		Duration:    25,
+		StartedAt:   start,
+		CompletedAt: start.Add(25 * time.Minute),
+	}
+
+	first, second, err := splitSession(session, start.Add(12*time.Minute+30*time.Second))
+	if err != nil {
+		t.Fatalf("splitSession: %v", err)
+	}
+	if first.Duration+second.Duration != 25 {
+		t.Errorf("parts last %d and %d minutes, want 25 in all", first.Duration, second.Duration)
+	}
+}
@@ -98,6 +98,30 @@ func (s *Storage) initTables() error {
 		completed BOOLEAN DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		completed_at DATETIME,
+		project_id INTEGER,
+		parent_id INTEGER,
+		auto_complete BOOLEAN DEFAULT 0,
+		estimated_pomodoros INTEGER DEFAULT 0,
+		due_date TEXT,
+		priority INTEGER DEFAULT 0,
+		sort_rank INTEGER DEFAULT 0,
+		recurrence TEXT,
+		occurrence INTEGER DEFAULT 1,
+		deleted_at DATETIME,
+		state TEXT,
+		FOREIGN KEY (user_id) REFERENCES users(id),
+		FOREIGN KEY (project_id) REFERENCES projects(id),
+		FOREIGN KEY (parent_id) REFERENCES tasks(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS projects (
+		id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		color TEXT,
+		archived BOOLEAN DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		archived_at DATETIME,
 		FOREIGN KEY (user_id) REFERENCES users(id)
 	);
 
@@ -108,10 +132,132 @@ func (s *Storage) initTables() error {
 		duration INTEGER NOT NULL,
 		started_at DATETIME NOT NULL,
 		completed_at DATETIME NOT NULL,
+		notes TEXT,
+		FOREIGN KEY (user_id) REFERENCES users(id),
+		FOREIGN KEY (task_id) REFERENCES tasks(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS session_pauses (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		paused_at DATETIME NOT NULL,
+		resumed_at DATETIME NOT NULL,
+		FOREIGN KEY (session_id) REFERENCES pomodoro_sessions(id),
+		FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS session_interruptions (
+		id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		session_id INTEGER NOT NULL,
+		kind TEXT NOT NULL,
+		note TEXT,
+		occurred_at DATETIME NOT NULL,
+		FOREIGN KEY (session_id) REFERENCES pomodoro_sessions(id),
+		FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS tags (
+		id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id),
+		UNIQUE(user_id, name)
+	);
+
+	CREATE TABLE IF NOT EXISTS task_tags (
+		task_id INTEGER NOT NULL,
+		tag_id INTEGER NOT NULL,
+		PRIMARY KEY (task_id, tag_id),
+		FOREIGN KEY (task_id) REFERENCES tasks(id),
+		FOREIGN KEY (tag_id) REFERENCES tags(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS session_tags (
+		session_id INTEGER NOT NULL,
+		tag_id INTEGER NOT NULL,
+		PRIMARY KEY (session_id, tag_id),
+		FOREIGN KEY (session_id) REFERENCES pomodoro_sessions(id),
+		FOREIGN KEY (tag_id) REFERENCES tags(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS workflow_states (
+		id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		position INTEGER DEFAULT 0,
+		terminal BOOLEAN DEFAULT 0,
+		FOREIGN KEY (user_id) REFERENCES users(id),
+		UNIQUE(user_id, name)
+	);
+
+	CREATE TABLE IF NOT EXISTS task_transitions (
+		id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		task_id INTEGER NOT NULL,
+		from_state TEXT,
+		to_state TEXT NOT NULL,
+		moved_at DATETIME NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id),
+		FOREIGN KEY (task_id) REFERENCES tasks(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS task_dependencies (
+		task_id INTEGER NOT NULL,
+		blocked_by_id INTEGER NOT NULL,
+		PRIMARY KEY (task_id, blocked_by_id),
+		FOREIGN KEY (task_id) REFERENCES tasks(id),
+		FOREIGN KEY (blocked_by_id) REFERENCES tasks(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS task_events (
+		id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		task_id INTEGER NOT NULL,
+		action TEXT NOT NULL,
+		before_values TEXT,
+		after_values TEXT,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id),
+		FOREIGN KEY (task_id) REFERENCES tasks(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS task_attachments (
+		id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		task_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		mime_type TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (user_id) REFERENCES users(id),
 		FOREIGN KEY (task_id) REFERENCES tasks(id)
 	);
 
+	CREATE TABLE IF NOT EXISTS purged_tasks (
+		id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		title TEXT NOT NULL,
+		purged_at DATETIME NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS task_templates (
+		id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		title_pattern TEXT NOT NULL,
+		description TEXT,
+		estimated_pomodoros INTEGER DEFAULT 0,
+		tags TEXT NOT NULL DEFAULT '[]',
+		checklist TEXT NOT NULL DEFAULT '[]',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+
 	CREATE TABLE IF NOT EXISTS water_reminders (
 		user_id INTEGER PRIMARY KEY,
 		enabled BOOLEAN DEFAULT 1,
@@ -171,6 +317,102 @@ func (s *Storage) runMigrations() error {
 		}
 	}
 
+	// Migration 2: Add project_id column to tasks table
+	if err := s.addColumnIfMissing("tasks", "project_id", "INTEGER REFERENCES projects(id)"); err != nil {
+		return err
+	}
+
+	// Migration 3: Add parent_id and auto_complete columns to tasks table for subtasks
+	if err := s.addColumnIfMissing("tasks", "parent_id", "INTEGER REFERENCES tasks(id)"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("tasks", "auto_complete", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Migration 4: Add estimated_pomodoros column to tasks table
+	if err := s.addColumnIfMissing("tasks", "estimated_pomodoros", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Migration 5: Add due_date, priority and sort_rank columns to tasks table
+	if err := s.addColumnIfMissing("tasks", "due_date", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("tasks", "priority", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("tasks", "sort_rank", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Migration 6: Add recurrence and occurrence columns to tasks table
+	if err := s.addColumnIfMissing("tasks", "recurrence", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("tasks", "occurrence", "INTEGER DEFAULT 1"); err != nil {
+		return err
+	}
+
+	// Migration 7: Add notes column to pomodoro_sessions table
+	if err := s.addColumnIfMissing("pomodoro_sessions", "notes", "TEXT"); err != nil {
+		return err
+	}
+
+	// Migration 8: Create and fill the full-text search index
+	if err := s.migrateSearchIndex(); err != nil {
+		return err
+	}
+
+	// Migration 9: Add deleted_at column to tasks table for the trash
+	if err := s.addColumnIfMissing("tasks", "deleted_at", "DATETIME"); err != nil {
+		return err
+	}
+
+	// Migration 10: Add state column to tasks table and put existing tasks in the default workflow
+	if err := s.addColumnIfMissing("tasks", "state", "TEXT"); err != nil {
+		return err
+	}
+	if err := fillTaskStates(s.db); err != nil {
+		return fmt.Errorf("failed to fill task states: %v", err)
+	}
+
+	// Migration 11: Add paused_seconds column to pomodoro_sessions table
+	if err := s.addColumnIfMissing("pomodoro_sessions", "paused_seconds", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Migration 12: Add mode column to pomodoro_sessions table
+	if err := s.addColumnIfMissing("pomodoro_sessions", "mode", "TEXT DEFAULT 'pomodoro'"); err != nil {
+		return err
+	}
+
+	// Migration 13: Add outcome and reason columns to pomodoro_sessions table
+	if err := s.addColumnIfMissing("pomodoro_sessions", "outcome", "TEXT DEFAULT 'completed'"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("pomodoro_sessions", "reason", "TEXT"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addColumnIfMissing adds a column to a table unless it already exists
+func (s *Storage) addColumnIfMissing(table, column, definition string) error {
+	var columnExists bool
+	query := `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`
+	if err := s.db.QueryRow(query, table, column).Scan(&columnExists); err != nil {
+		return fmt.Errorf("failed to check for %s column: %v", column, err)
+	}
+
+	if !columnExists {
+		_, err := s.db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+		if err != nil {
+			return fmt.Errorf("failed to add %s column: %v", column, err)
+		}
+	}
+
 	return nil
 }
 
@@ -239,22 +481,194 @@ func (s *Storage) UpdateUserLanguage(userID int64, language string) error {
 	}, 3)
 }
 
-// CreateTask creates a new task
+// CreateTask creates a new task. A task without a state starts in the first open
+// workflow state, or in the terminal one when it is already completed. The parent of a
+// new subtask has its completion rolled up in the same transaction.
 func (s *Storage) CreateTask(task *Task) error {
 	return retryOnBusy(func() error {
-		query := `INSERT INTO tasks (id, user_id, title, description, completed, created_at) 
-				  VALUES (?, ?, ?, ?, ?, ?)`
-		_, err := s.db.Exec(query, task.ID, task.UserID, task.Title, task.Description,
-			task.Completed, task.CreatedAt.Format(time.RFC3339))
-		return err
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := createTask(tx, task); err != nil {
+			return err
+		}
+
+		// A new open subtask reopens an auto-completed parent
+		if task.ParentID != nil {
+			if err := rollUpCompletion(tx, *task.ParentID, task.UserID); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// CreateTasks creates several tasks in one transaction, so either all or none of them are
+// created. tags holds the tag names of each task by task ID; missing tags are created.
+// Parents must come before their subtasks. Existing parents of new subtasks have their
+// completion rolled up in the same transaction.
+func (s *Storage) CreateTasks(tasks []*Task, tags map[int64][]string) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for _, task := range tasks {
+			if err := createTask(tx, task); err != nil {
+				return err
+			}
+			task.Tags = nil
+			for _, name := range tags[task.ID] {
+				tag, err := getOrCreateTag(tx, task.UserID, name)
+				if err != nil {
+					return err
+				}
+				if err := addTaskTag(tx, task.ID, tag.ID); err != nil {
+					return err
+				}
+				task.Tags = append(task.Tags, *tag)
+			}
+		}
+
+		// New open subtasks reopen auto-completed parents that already existed
+		created := make(map[int64]bool, len(tasks))
+		for _, task := range tasks {
+			created[task.ID] = true
+		}
+		for _, task := range tasks {
+			if task.ParentID != nil && !created[*task.ParentID] {
+				if err := rollUpCompletion(tx, *task.ParentID, task.UserID); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Commit()
 	}, 3)
 }
 
-// GetTasks retrieves all tasks for a user
-func (s *Storage) GetTasks(userID int64) ([]Task, error) {
-	query := `SELECT id, user_id, title, description, completed, created_at, completed_at 
-	          FROM tasks WHERE user_id = ? ORDER BY created_at DESC`
-	rows, err := s.db.Query(query, userID)
+// createTask inserts a task within a transaction, putting it in the initial workflow state
+// when it has none
+func createTask(tx *sql.Tx, task *Task) error {
+	if task.State == "" {
+		states, err := getWorkflowStates(tx, task.UserID)
+		if err != nil {
+			return err
+		}
+		task.State = workflowStateFor(states, task.Completed)
+	}
+
+	var completedAtStr *string
+	if task.CompletedAt != nil {
+		s := task.CompletedAt.Format(time.RFC3339)
+		completedAtStr = &s
+	}
+
+	query := `INSERT INTO tasks (id, user_id, title, description, completed, completed_at, created_at, project_id, parent_id, 
+			  auto_complete, estimated_pomodoros, due_date, priority, sort_rank, recurrence, occurrence, state) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := tx.Exec(query, task.ID, task.UserID, task.Title, task.Description, task.Completed, completedAtStr,
+		task.CreatedAt.Format(time.RFC3339), task.ProjectID, task.ParentID, task.AutoComplete,
+		task.EstimatedPomodoros, nullableString(task.DueDate), task.Priority, task.SortRank,
+		nullableString(task.Recurrence), task.Occurrence, task.State)
+	if err != nil {
+		return err
+	}
+	if err := indexTask(tx, task); err != nil {
+		return err
+	}
+	return recordTaskEvent(tx, task.UserID, task.ID, TaskEventCreate, nil, taskSnapshot(task))
+}
+
+// taskColumns is the column list shared by all task queries, in scanTask order
+const taskColumns = `id, user_id, title, description, completed, created_at, completed_at, project_id, parent_id, auto_complete, estimated_pomodoros, 
+	due_date, priority, sort_rank, recurrence, occurrence, deleted_at, state`
+
+// GetTasks retrieves the tasks of a user matching a filter, leaving out those in the trash
+func (s *Storage) GetTasks(userID int64, filter TaskFilter) ([]Task, error) {
+	query := `SELECT ` + taskColumns + ` 
+	          FROM tasks WHERE user_id = ? AND deleted_at IS NULL`
+	args := []interface{}{userID}
+
+	if tags := normalizeTagNames(filter.Tags); len(tags) > 0 {
+		clause, tagArgs := tagFilterClause("id", "task_tags", "task_id", tags)
+		query += clause
+		args = append(args, tagArgs...)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	switch filter.Mode {
+	case TaskModeAll:
+		query += ` ORDER BY sort_rank, created_at DESC`
+	case TaskModeToday:
+		query += ` AND due_date = ? ORDER BY completed, priority DESC, sort_rank, created_at DESC`
+		args = append(args, today)
+	case TaskModeOverdue:
+		query += ` AND completed = 0 AND due_date < ? ORDER BY due_date, priority DESC, sort_rank`
+		args = append(args, today)
+	case TaskModeUpcoming:
+		query += ` AND due_date > ? ORDER BY due_date, priority DESC, sort_rank`
+		args = append(args, today)
+	case TaskModePriority:
+		query += ` ORDER BY completed, priority DESC, due_date IS NULL, due_date, sort_rank, created_at DESC`
+	case TaskModeReady:
+		query += ` AND completed = 0 AND NOT EXISTS (
+		             SELECT 1 FROM task_dependencies d JOIN tasks b ON b.id = d.blocked_by_id
+		             WHERE d.task_id = tasks.id AND b.completed = 0 AND b.deleted_at IS NULL
+		           ) ORDER BY priority DESC, due_date IS NULL, due_date, sort_rank, created_at DESC`
+	default:
+		return nil, fmt.Errorf("unknown task mode: %s", filter.Mode)
+	}
+
+	tasks, err := s.queryTasks(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachTaskTags(userID, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, s.attachTaskDependencies(userID, tasks)
+}
+
+// GetTask retrieves a single task owned by a user that is not in the trash
+func (s *Storage) GetTask(taskID, userID int64) (*Task, error) {
+	return getTask(s.db, taskID, userID)
+}
+
+// getTask is GetTask on either the database or a transaction
+func getTask(db queryer, taskID, userID int64) (*Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
+	task, err := scanTask(db.QueryRow(query, taskID, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("task not found")
+	}
+	return task, err
+}
+
+// attachTaskTags fills in the Tags field of each task
+func (s *Storage) attachTaskTags(userID int64, tasks []Task) error {
+	tags, err := s.getTaskTags(userID)
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].Tags = tags[tasks[i].ID]
+	}
+	return nil
+}
+
+// queryTasks runs a task query and scans every row
+func (s *Storage) queryTasks(query string, args ...interface{}) ([]Task, error) {
+	return selectTasks(s.db, query, args...)
+}
+
+// selectTasks is queryTasks on either the database or a transaction
+func selectTasks(db queryer, query string, args ...interface{}) ([]Task, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -262,79 +676,560 @@ func (s *Storage) GetTasks(userID int64) ([]Task, error) {
 
 	var tasks []Task
 	for rows.Next() {
-		var task Task
-		var createdAtStr string
-		var completedAtStr sql.NullString
-
-		err := rows.Scan(&task.ID, &task.UserID, &task.Title, &task.Description,
-			&task.Completed, &createdAtStr, &completedAtStr)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, err
 		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, nil
+}
+
+// scanTask scans a task row selected with taskColumns
+func scanTask(row interface{ Scan(...interface{}) error }) (*Task, error) {
+	task := &Task{}
+	var createdAtStr string
+	var completedAtStr, dueDate, recurrence, deletedAtStr, state sql.NullString
+
+	err := row.Scan(&task.ID, &task.UserID, &task.Title, &task.Description,
+		&task.Completed, &createdAtStr, &completedAtStr, &task.ProjectID, &task.ParentID, &task.AutoComplete,
+		&task.EstimatedPomodoros, &dueDate, &task.Priority, &task.SortRank, &recurrence, &task.Occurrence, &deletedAtStr, &state)
+	if err != nil {
+		return nil, err
+	}
+	task.DueDate = dueDate.String
+	task.Recurrence = recurrence.String
+	task.State = state.String
 
-		task.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
+	task.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %v", err)
+	}
+
+	if completedAtStr.Valid {
+		t, err := time.Parse(time.RFC3339, completedAtStr.String)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse created_at: %v", err)
+			return nil, fmt.Errorf("failed to parse completed_at: %v", err)
 		}
+		task.CompletedAt = &t
+	}
 
-		if completedAtStr.Valid {
-			t, err := time.Parse(time.RFC3339, completedAtStr.String)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse completed_at: %v", err)
-			}
-			task.CompletedAt = &t
+	if deletedAtStr.Valid {
+		t, err := time.Parse(time.RFC3339, deletedAtStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse deleted_at: %v", err)
 		}
-
-		tasks = append(tasks, task)
+		task.DeletedAt = &t
 	}
-	return tasks, nil
+
+	return task, nil
 }
 
-// UpdateTask updates a task
+// UpdateTask updates a task. When only the completion of the task changes, it is moved to
+// the terminal workflow state or back to the first open one. Every change of state is
+// recorded as a transition, and every change as an event in the audit log.
 func (s *Storage) UpdateTask(task *Task) error {
 	return retryOnBusy(func() error {
-		query := `UPDATE tasks SET title = ?, description = ?, completed = ?, completed_at = ? 
-				  WHERE id = ? AND user_id = ?`
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
 
-		var completedAtStr *string
-		if task.CompletedAt != nil {
-			s := task.CompletedAt.Format(time.RFC3339)
-			completedAtStr = &s
+		if err := updateTask(tx, task); err != nil {
+			return err
 		}
+		return tx.Commit()
+	}, 3)
+}
 
-		_, err := s.db.Exec(query, task.Title, task.Description, task.Completed,
-			completedAtStr, task.ID, task.UserID)
+// updateTask is UpdateTask within a transaction
+func updateTask(tx *sql.Tx, task *Task) error {
+	var previous Task
+	var previousState sql.NullString
+	query := `SELECT title, COALESCE(description, ''), completed, project_id, state FROM tasks WHERE id = ? AND user_id = ?`
+	err := tx.QueryRow(query, task.ID, task.UserID).Scan(&previous.Title, &previous.Description,
+		&previous.Completed, &previous.ProjectID, &previousState)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
 		return err
-	}, 3)
+	}
+	previous.State = previousState.String
+
+	if task.State == "" {
+		task.State = previous.State
+	}
+	if task.Completed != previous.Completed && task.State == previous.State {
+		states, err := getWorkflowStates(tx, task.UserID)
+		if err != nil {
+			return err
+		}
+		task.State = workflowStateFor(states, task.Completed)
+	}
+
+	query = `UPDATE tasks SET title = ?, description = ?, completed = ?, completed_at = ?, project_id = ?, state = ? 
+			  WHERE id = ? AND user_id = ?`
+
+	var completedAtStr *string
+	if task.CompletedAt != nil {
+		s := task.CompletedAt.Format(time.RFC3339)
+		completedAtStr = &s
+	}
+
+	_, err = tx.Exec(query, task.Title, task.Description, task.Completed,
+		completedAtStr, task.ProjectID, task.State, task.ID, task.UserID)
+	if err != nil {
+		return err
+	}
+
+	if task.State != previous.State {
+		transition := &TaskTransition{
+			ID:        GenerateID(),
+			UserID:    task.UserID,
+			TaskID:    task.ID,
+			FromState: previous.State,
+			ToState:   task.State,
+			MovedAt:   time.Now(),
+		}
+		if err := insertTaskTransition(tx, transition); err != nil {
+			return err
+		}
+	}
+
+	if err := recordTaskChanges(tx, &previous, task); err != nil {
+		return err
+	}
+
+	return indexTask(tx, task)
+}
+
+// recordTaskChanges adds an audit log entry with the fields UpdateTask changed, if any
+func recordTaskChanges(db execer, previous, task *Task) error {
+	before := make(map[string]interface{})
+	after := make(map[string]interface{})
+	if previous.Title != task.Title {
+		before["title"], after["title"] = previous.Title, task.Title
+	}
+	if previous.Description != task.Description {
+		before["description"], after["description"] = previous.Description, task.Description
+	}
+	if previous.Completed != task.Completed {
+		before["completed"], after["completed"] = previous.Completed, task.Completed
+	}
+	if !sameOptionalID(previous.ProjectID, task.ProjectID) {
+		before["project_id"], after["project_id"] = auditValue(previous.ProjectID), auditValue(task.ProjectID)
+	}
+	if previous.State != task.State {
+		before["state"], after["state"] = previous.State, task.State
+	}
+	if len(after) == 0 {
+		return nil
+	}
+
+	action := TaskEventUpdate
+	if previous.Completed != task.Completed {
+		action = TaskEventReopen
+		if task.Completed {
+			action = TaskEventComplete
+		}
+	}
+	return recordTaskEvent(db, task.UserID, task.ID, action, before, after)
 }
 
-// DeleteTask deletes a task
+// DeleteTask moves a task together with all of its subtasks to the trash. Trashed tasks
+// keep their rows, so sessions recorded on them still resolve until they are purged. The
+// parent has its completion rolled up in the same transaction.
 func (s *Storage) DeleteTask(taskID, userID int64) error {
 	return retryOnBusy(func() error {
-		query := `DELETE FROM tasks WHERE id = ? AND user_id = ?`
-		_, err := s.db.Exec(query, taskID, userID)
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		task, err := getTask(tx, taskID, userID)
+		if err != nil {
+			return err
+		}
+		if err := deleteTask(tx, taskID, userID); err != nil {
+			return err
+		}
+
+		// The remaining subtasks may all be completed now
+		if task.ParentID != nil {
+			if err := rollUpCompletion(tx, *task.ParentID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// deleteTask is DeleteTask within a transaction
+func deleteTask(tx *sql.Tx, taskID, userID int64) error {
+	query := `WITH RECURSIVE subtree(id) AS (
+	            SELECT id FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+	            UNION SELECT t.id FROM tasks t JOIN subtree ON t.parent_id = subtree.id
+	          )
+	          SELECT id FROM tasks WHERE id IN (SELECT id FROM subtree) AND deleted_at IS NULL`
+	taskIDs, err := queryIDs(tx, query, taskID, userID)
+	if err != nil {
 		return err
+	}
+
+	deletedAt := time.Now().Format(time.RFC3339)
+	for _, id := range taskIDs {
+		if _, err := tx.Exec(`UPDATE tasks SET deleted_at = ? WHERE id = ?`, deletedAt, id); err != nil {
+			return err
+		}
+		after := map[string]interface{}{"deleted_at": deletedAt}
+		if err := recordTaskEvent(tx, userID, id, TaskEventDelete, nil, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryIDs runs a query selecting a single ID column and returns every ID
+func queryIDs(db queryer, query string, args ...interface{}) ([]int64, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SetTaskParent moves a task under another task, or to the top level when parentID is nil.
+// The old and the new parent have their completion rolled up in the same transaction.
+func (s *Storage) SetTaskParent(taskID, userID int64, parentID *int64) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		task, err := getTask(tx, taskID, userID)
+		if err != nil {
+			return err
+		}
+		if err := updateTaskColumn(tx, taskID, userID, "parent_id", parentID); err != nil {
+			return err
+		}
+
+		// Both the old and the new parent may have changed their completion state
+		if task.ParentID != nil {
+			if err := rollUpCompletion(tx, *task.ParentID, userID); err != nil {
+				return err
+			}
+		}
+		if parentID != nil {
+			if err := rollUpCompletion(tx, *parentID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// SetTaskAutoComplete sets whether a task completes itself once all of its subtasks are
+// completed, and rolls its completion up in the same transaction
+func (s *Storage) SetTaskAutoComplete(taskID, userID int64, autoComplete bool) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := updateTaskColumn(tx, taskID, userID, "auto_complete", autoComplete); err != nil {
+			return err
+		}
+		if err := rollUpCompletion(tx, taskID, userID); err != nil {
+			return err
+		}
+		return tx.Commit()
 	}, 3)
 }
 
+// SetTaskEstimate sets the number of pomodoros a task is expected to take
+func (s *Storage) SetTaskEstimate(taskID, userID int64, estimate int) error {
+	return s.setTaskColumn(taskID, userID, "estimated_pomodoros", estimate)
+}
+
+// SetTaskDueDate sets the due date of a task, or clears it when dueDate is empty
+func (s *Storage) SetTaskDueDate(taskID, userID int64, dueDate string) error {
+	return s.setTaskColumn(taskID, userID, "due_date", nullableString(dueDate))
+}
+
+// SetTaskPriority sets the priority of a task
+func (s *Storage) SetTaskPriority(taskID, userID int64, priority int) error {
+	return s.setTaskColumn(taskID, userID, "priority", priority)
+}
+
+// SetTaskRecurrence sets the recurrence rule of a task, or clears it when rule is empty
+func (s *Storage) SetTaskRecurrence(taskID, userID int64, rule string) error {
+	return s.setTaskColumn(taskID, userID, "recurrence", nullableString(rule))
+}
+
+// setTaskColumn updates a single column of a task, recording the change in the audit log.
+// The column name doubles as the field name in the log.
+func (s *Storage) setTaskColumn(taskID, userID int64, column string, value interface{}) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := updateTaskColumn(tx, taskID, userID, column, value); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// updateTaskColumn is setTaskColumn within a transaction
+func updateTaskColumn(tx *sql.Tx, taskID, userID int64, column string, value interface{}) error {
+	var previous interface{}
+	query := fmt.Sprintf(`SELECT %s FROM tasks WHERE id = ? AND user_id = ?`, column)
+	err := tx.QueryRow(query, taskID, userID).Scan(&previous)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(`UPDATE tasks SET %s = ? WHERE id = ? AND user_id = ?`, column)
+	if _, err := tx.Exec(query, value, taskID, userID); err != nil {
+		return err
+	}
+
+	before, after := auditValue(previous), auditValue(value)
+	if fmt.Sprint(before) != fmt.Sprint(after) {
+		return recordTaskEvent(tx, userID, taskID, TaskEventUpdate,
+			map[string]interface{}{column: before}, map[string]interface{}{column: after})
+	}
+	return nil
+}
+
+// ReorderTasks stores the given order of tasks as their manual sort rank
+func (s *Storage) ReorderTasks(userID int64, taskIDs []int64) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		stmt, err := tx.Prepare(`UPDATE tasks SET sort_rank = ? WHERE id = ? AND user_id = ?`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for i, taskID := range taskIDs {
+			if _, err := stmt.Exec(i+1, taskID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// nullableString maps an empty string to NULL
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// CountSessionsByTask returns the number of pomodoros ever completed for each task of a user.
+// Stopwatch sessions are not pomodoros and abandoned ones were not finished, so both are left out.
+func (s *Storage) CountSessionsByTask(userID int64) (map[int64]int, error) {
+	query := `SELECT task_id, COUNT(*) FROM pomodoro_sessions 
+	          WHERE user_id = ? AND task_id IS NOT NULL AND COALESCE(mode, ?) = ? AND COALESCE(outcome, ?) = ?
+	          GROUP BY task_id`
+	rows, err := s.db.Query(query, userID, TimerModePomodoro, TimerModePomodoro,
+		SessionOutcomeCompleted, SessionOutcomeCompleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var taskID int64
+		var count int
+		if err := rows.Scan(&taskID, &count); err != nil {
+			return nil, err
+		}
+		counts[taskID] = count
+	}
+	return counts, nil
+}
+
+// GetSubtasks retrieves the direct subtasks of a task that are not in the trash
+func (s *Storage) GetSubtasks(parentID, userID int64) ([]Task, error) {
+	return getSubtasks(s.db, parentID, userID)
+}
+
+// getSubtasks is GetSubtasks on either the database or a transaction
+func getSubtasks(db queryer, parentID, userID int64) ([]Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE parent_id = ? AND user_id = ? AND deleted_at IS NULL`
+	return selectTasks(db, query, parentID, userID)
+}
+
+// sessionColumns is the column list shared by all Pomodoro session queries, in scanSession order
+const sessionColumns = `id, user_id, task_id, duration, started_at, completed_at, notes, paused_seconds, mode, outcome, reason`
+
 // CreatePomodoroSession creates a new Pomodoro session
 func (s *Storage) CreatePomodoroSession(session *PomodoroSession) error {
 	return retryOnBusy(func() error {
-		query := `INSERT INTO pomodoro_sessions (id, user_id, task_id, duration, started_at, completed_at) 
-				  VALUES (?, ?, ?, ?, ?, ?)`
-		_, err := s.db.Exec(query, session.ID, session.UserID, session.TaskID, session.Duration,
-			session.StartedAt, session.CompletedAt)
-		return err
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := insertSession(tx, session); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// CreatePomodoroSessions creates several Pomodoro sessions at once, such as the per-task
+// parts of a timer run
+func (s *Storage) CreatePomodoroSessions(sessions []PomodoroSession) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for i := range sessions {
+			if err := insertSession(tx, &sessions[i]); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
 	}, 3)
 }
 
-// GetSessions retrieves Pomodoro sessions for a user within a date range
-func (s *Storage) GetSessions(userID int64, startDate, endDate time.Time) ([]PomodoroSession, error) {
-	query := `SELECT id, user_id, task_id, duration, started_at, completed_at 
+// insertSession stores a Pomodoro session with its pauses and interruptions and indexes
+// its notes
+func insertSession(db execer, session *PomodoroSession) error {
+	query := `INSERT INTO pomodoro_sessions (id, user_id, task_id, duration, started_at, completed_at, notes, paused_seconds, mode, outcome, reason) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.Exec(query, session.ID, session.UserID, session.TaskID, session.Duration,
+		session.StartedAt, session.CompletedAt, nullableString(session.Notes), session.PausedSeconds,
+		sessionMode(session.Mode), sessionOutcome(session.Outcome), nullableString(session.Reason))
+	if err != nil {
+		return err
+	}
+	if err := insertSessionSegments(db, session); err != nil {
+		return err
+	}
+	return indexSession(db, session)
+}
+
+// sessionMode maps the mode of a session saved before the stopwatch existed to pomodoro
+func sessionMode(mode string) string {
+	if mode == "" {
+		return TimerModePomodoro
+	}
+	return mode
+}
+
+// sessionOutcome maps the outcome of a session saved before abandoned sessions were
+// recorded to completed
+func sessionOutcome(outcome string) string {
+	if outcome == "" {
+		return SessionOutcomeCompleted
+	}
+	return outcome
+}
+
+// GetSessions retrieves Pomodoro sessions for a user within a date range,
+// optionally limited to sessions carrying all of the given tags
+func (s *Storage) GetSessions(userID int64, startDate, endDate time.Time, tags []string) ([]PomodoroSession, error) {
+	query := `SELECT ` + sessionColumns + ` 
 	          FROM pomodoro_sessions 
-	          WHERE user_id = ? AND completed_at BETWEEN ? AND ?
-	          ORDER BY completed_at DESC`
-	rows, err := s.db.Query(query, userID, startDate, endDate)
+	          WHERE user_id = ? AND completed_at BETWEEN ? AND ?`
+	args := []interface{}{userID, startDate, endDate}
+
+	if tags := normalizeTagNames(tags); len(tags) > 0 {
+		clause, tagArgs := tagFilterClause("id", "session_tags", "session_id", tags)
+		query += clause
+		args = append(args, tagArgs...)
+	}
+	query += ` ORDER BY completed_at DESC`
+
+	sessions, err := s.querySessions(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachSessionSegments(userID, sessions); err != nil {
+		return nil, err
+	}
+	return sessions, s.attachSessionTags(userID, sessions)
+}
+
+// GetPomodoroSession retrieves a single Pomodoro session owned by a user
+func (s *Storage) GetPomodoroSession(sessionID, userID int64) (*PomodoroSession, error) {
+	query := `SELECT ` + sessionColumns + ` 
+	          FROM pomodoro_sessions WHERE id = ? AND user_id = ?`
+	session, err := scanSession(s.db.QueryRow(query, sessionID, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("pomodoro session not found")
+	}
+	return session, err
+}
+
+// SetSessionNotes sets the notes of a Pomodoro session
+func (s *Storage) SetSessionNotes(sessionID, userID int64, notes string) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		query := `UPDATE pomodoro_sessions SET notes = ? WHERE id = ? AND user_id = ?`
+		result, err := tx.Exec(query, nullableString(notes), sessionID, userID)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return nil
+		}
+		session := &PomodoroSession{ID: sessionID, UserID: userID, Notes: notes}
+		if err := indexSession(tx, session); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// querySessions runs a Pomodoro session query and scans every row
+func (s *Storage) querySessions(query string, args ...interface{}) ([]PomodoroSession, error) {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -342,17 +1237,46 @@ func (s *Storage) GetSessions(userID int64, startDate, endDate time.Time) ([]Pom
 
 	var sessions []PomodoroSession
 	for rows.Next() {
-		var session PomodoroSession
-		err := rows.Scan(&session.ID, &session.UserID, &session.TaskID, &session.Duration,
-			&session.StartedAt, &session.CompletedAt)
+		session, err := scanSession(rows)
 		if err != nil {
 			return nil, err
 		}
-		sessions = append(sessions, session)
+		sessions = append(sessions, *session)
 	}
 	return sessions, nil
 }
 
+// scanSession scans a Pomodoro session row selected with sessionColumns
+func scanSession(row interface{ Scan(...interface{}) error }) (*PomodoroSession, error) {
+	session := &PomodoroSession{}
+	var notes sql.NullString
+	var pausedSeconds sql.NullInt64
+	var mode, outcome, reason sql.NullString
+	err := row.Scan(&session.ID, &session.UserID, &session.TaskID, &session.Duration,
+		&session.StartedAt, &session.CompletedAt, &notes, &pausedSeconds, &mode, &outcome, &reason)
+	if err != nil {
+		return nil, err
+	}
+	session.Notes = notes.String
+	session.PausedSeconds = int(pausedSeconds.Int64)
+	session.Mode = sessionMode(mode.String)
+	session.Outcome = sessionOutcome(outcome.String)
+	session.Reason = reason.String
+	return session, nil
+}
+
+// attachSessionTags fills in the Tags field of each session
+func (s *Storage) attachSessionTags(userID int64, sessions []PomodoroSession) error {
+	tags, err := s.getSessionTags(userID)
+	if err != nil {
+		return err
+	}
+	for i := range sessions {
+		sessions[i].Tags = tags[sessions[i].ID]
+	}
+	return nil
+}
+
 // GetWaterReminderSettings retrieves water reminder settings for a user
 func (s *Storage) GetWaterReminderSettings(userID int64) (*WaterReminderSettings, error) {
 	query := `SELECT enabled, interval_mins, custom_interval_mins, last_reminder FROM water_reminders WHERE user_id = ?`
@@ -387,6 +1311,22 @@ func (s *Storage) Close() error {
 // ClearData clears all data from the database
 func (s *Storage) ClearData() {
 	s.db.Exec("DELETE FROM tasks")
+	s.db.Exec("DELETE FROM projects")
+	s.db.Exec("DELETE FROM task_tags")
+	s.db.Exec("DELETE FROM session_tags")
+	s.db.Exec("DELETE FROM tags")
+	s.db.Exec("DELETE FROM workflow_states")
+	s.db.Exec("DELETE FROM task_transitions")
+	s.db.Exec("DELETE FROM task_dependencies")
+	s.db.Exec("DELETE FROM task_events")
+	s.db.Exec("DELETE FROM task_templates")
+	s.db.Exec("DELETE FROM task_attachments")
+	s.db.Exec("DELETE FROM purged_tasks")
+	s.db.Exec("DELETE FROM tasks_fts")
+	s.db.Exec("DELETE FROM retros_fts")
+	s.db.Exec("DELETE FROM sessions_fts")
+	s.db.Exec("DELETE FROM session_pauses")
+	s.db.Exec("DELETE FROM session_interruptions")
 	s.db.Exec("DELETE FROM pomodoro_sessions")
 	s.db.Exec("DELETE FROM water_reminders")
 	s.db.Exec("DELETE FROM daily_retros")
@@ -467,45 +1407,46 @@ func (s *Storage) GetDailyRetro(userID int64, date string) (*DailyRetro, error)
 // SaveDailyRetro saves or updates a daily retro
 func (s *Storage) SaveDailyRetro(retro *DailyRetro) error {
 	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
 		query := `INSERT INTO daily_retros (id, user_id, date, retro_notes, plan_notes, created_at, updated_at) 
 				  VALUES (?, ?, ?, ?, ?, ?, ?)
 				  ON CONFLICT(user_id, date) DO UPDATE SET
 				  retro_notes = excluded.retro_notes,
 				  plan_notes = excluded.plan_notes,
 				  updated_at = excluded.updated_at`
-		_, err := s.db.Exec(query, retro.ID, retro.UserID, retro.Date, retro.RetroNotes, retro.PlanNotes,
+		_, err = tx.Exec(query, retro.ID, retro.UserID, retro.Date, retro.RetroNotes, retro.PlanNotes,
 			retro.CreatedAt, retro.UpdatedAt)
-		return err
+		if err != nil {
+			return err
+		}
+		if err := indexRetro(tx, retro); err != nil {
+			return err
+		}
+		return tx.Commit()
 	}, 3)
 }
 
-// GetCompletedTasksForDate retrieves tasks completed on a specific date
+// GetCompletedTasksForDate retrieves tasks completed on a specific date. Completed tasks
+// are the ones in the terminal workflow state, see UpdateTask.
 func (s *Storage) GetCompletedTasksForDate(userID int64, date string) ([]Task, error) {
 	// SQLite date function can match the date part of datetime
-	query := `SELECT id, user_id, title, description, completed, created_at, completed_at 
+	query := `SELECT ` + taskColumns + ` 
 	          FROM tasks 
 	          WHERE user_id = ? 
 	          AND completed = 1 
+	          AND deleted_at IS NULL
 	          AND date(completed_at) = ?
 	          ORDER BY completed_at DESC`
-
-	rows, err := s.db.Query(query, userID, date)
+	tasks, err := s.queryTasks(query, userID, date)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var tasks []Task
-	for rows.Next() {
-		var task Task
-		err := rows.Scan(&task.ID, &task.UserID, &task.Title, &task.Description,
-			&task.Completed, &task.CreatedAt, &task.CompletedAt)
-		if err != nil {
-			return nil, err
-		}
-		tasks = append(tasks, task)
-	}
-	return tasks, nil
+	return tasks, s.attachTaskTags(userID, tasks)
 }
 
 // GetSetting retrieves a setting value by key
@@ -0,0 +1,108 @@
+package backend
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// attachmentColumns is the column list shared by all attachment queries, in scanAttachment order
+const attachmentColumns = `id, user_id, task_id, name, hash, size, mime_type, created_at`
+
+// CreateAttachment records a file attached to a task
+func (s *Storage) CreateAttachment(attachment *Attachment) error {
+	return retryOnBusy(func() error {
+		return insertAttachment(s.db, attachment)
+	}, 3)
+}
+
+// insertAttachment is CreateAttachment on either the database or a transaction
+func insertAttachment(db execer, attachment *Attachment) error {
+	query := `INSERT OR REPLACE INTO task_attachments (id, user_id, task_id, name, hash, size, mime_type, created_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.Exec(query, attachment.ID, attachment.UserID, attachment.TaskID, attachment.Name, attachment.Hash,
+		attachment.Size, attachment.MimeType, attachment.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+// GetAttachments retrieves the files attached to a task, oldest first
+func (s *Storage) GetAttachments(taskID, userID int64) ([]Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM task_attachments WHERE task_id = ? AND user_id = ? ORDER BY created_at, id`
+	return s.queryAttachments(query, taskID, userID)
+}
+
+// GetAttachment retrieves a single attachment owned by a user
+func (s *Storage) GetAttachment(attachmentID, userID int64) (*Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM task_attachments WHERE id = ? AND user_id = ?`
+	attachments, err := s.queryAttachments(query, attachmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return nil, fmt.Errorf("attachment not found")
+	}
+	return &attachments[0], nil
+}
+
+// DeleteAttachment removes an attachment from its task. The stored file is left for
+// the orphaned file cleanup, since other attachments may share it.
+func (s *Storage) DeleteAttachment(attachmentID, userID int64) error {
+	return retryOnBusy(func() error {
+		query := `DELETE FROM task_attachments WHERE id = ? AND user_id = ?`
+		_, err := s.db.Exec(query, attachmentID, userID)
+		return err
+	}, 3)
+}
+
+// GetAttachmentHashes returns the hashes of every stored file still attached to a task,
+// across all users
+func (s *Storage) GetAttachmentHashes() (map[string]bool, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT hash FROM task_attachments`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]bool)
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes[hash] = true
+	}
+	return hashes, rows.Err()
+}
+
+// getAllAttachmentsForUser returns every attachment of a user for export
+func (s *Storage) getAllAttachmentsForUser(userID int64) ([]Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM task_attachments WHERE user_id = ?`
+	return s.queryAttachments(query, userID)
+}
+
+// queryAttachments runs an attachment query and scans every row
+func (s *Storage) queryAttachments(query string, args ...interface{}) ([]Attachment, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []Attachment{}
+	for rows.Next() {
+		var attachment Attachment
+		var mimeType sql.NullString
+		var createdAtStr string
+		if err := rows.Scan(&attachment.ID, &attachment.UserID, &attachment.TaskID, &attachment.Name,
+			&attachment.Hash, &attachment.Size, &mimeType, &createdAtStr); err != nil {
+			return nil, err
+		}
+		attachment.MimeType = mimeType.String
+		attachment.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %v", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, rows.Err()
+}
@@ -0,0 +1,112 @@
+package backend
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// recordTaskEvent adds an entry to the task audit log
+func recordTaskEvent(db execer, userID, taskID int64, action string, before, after map[string]interface{}) error {
+	event := &TaskEvent{
+		ID:        GenerateID(),
+		UserID:    userID,
+		TaskID:    taskID,
+		Action:    action,
+		Before:    before,
+		After:     after,
+		CreatedAt: time.Now(),
+	}
+	return insertTaskEvent(db, event)
+}
+
+// insertTaskEvent stores an audit log entry as is
+func insertTaskEvent(db execer, event *TaskEvent) error {
+	before, err := marshalAuditValues(event.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalAuditValues(event.After)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT OR REPLACE INTO task_events (id, user_id, task_id, action, before_values, after_values, created_at) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err = db.Exec(query, event.ID, event.UserID, event.TaskID, event.Action, before, after,
+		event.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+// marshalAuditValues encodes the values of an audit log entry, mapping no values to NULL
+func marshalAuditValues(values map[string]interface{}) (interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit values: %v", err)
+	}
+	return string(data), nil
+}
+
+// taskEventColumns is the column list shared by all audit log queries, in scanTaskEvent order
+const taskEventColumns = `e.id, e.user_id, e.task_id, COALESCE(t.title, ''), e.action, e.before_values, e.after_values, e.created_at`
+
+// GetTaskEvents retrieves the audit log of a task, oldest first
+func (s *Storage) GetTaskEvents(taskID, userID int64) ([]TaskEvent, error) {
+	query := `SELECT ` + taskEventColumns + ` FROM task_events e LEFT JOIN tasks t ON t.id = e.task_id
+	          WHERE e.task_id = ? AND e.user_id = ? ORDER BY e.created_at, e.id`
+	return s.queryTaskEvents(query, taskID, userID)
+}
+
+// GetTaskEventsBetween retrieves the audit log entries of a user recorded within a time range
+func (s *Storage) GetTaskEventsBetween(userID int64, start, end time.Time) ([]TaskEvent, error) {
+	query := `SELECT ` + taskEventColumns + ` FROM task_events e LEFT JOIN tasks t ON t.id = e.task_id
+	          WHERE e.user_id = ? AND e.created_at >= ? AND e.created_at < ? ORDER BY e.created_at, e.id`
+	return s.queryTaskEvents(query, userID, start.Format(time.RFC3339), end.Format(time.RFC3339))
+}
+
+// getAllTaskEventsForUser returns the whole audit log of a user for export
+func (s *Storage) getAllTaskEventsForUser(userID int64) ([]TaskEvent, error) {
+	query := `SELECT ` + taskEventColumns + ` FROM task_events e LEFT JOIN tasks t ON t.id = e.task_id
+	          WHERE e.user_id = ? ORDER BY e.created_at, e.id`
+	return s.queryTaskEvents(query, userID)
+}
+
+// queryTaskEvents runs an audit log query and scans every row
+func (s *Storage) queryTaskEvents(query string, args ...interface{}) ([]TaskEvent, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []TaskEvent{}
+	for rows.Next() {
+		var event TaskEvent
+		var before, after sql.NullString
+		var createdAtStr string
+		if err := rows.Scan(&event.ID, &event.UserID, &event.TaskID, &event.TaskTitle, &event.Action,
+			&before, &after, &createdAtStr); err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			if err := json.Unmarshal([]byte(before.String), &event.Before); err != nil {
+				return nil, fmt.Errorf("failed to parse audit values: %v", err)
+			}
+		}
+		if after.Valid {
+			if err := json.Unmarshal([]byte(after.String), &event.After); err != nil {
+				return nil, fmt.Errorf("failed to parse audit values: %v", err)
+			}
+		}
+		event.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %v", err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
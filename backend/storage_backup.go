@@ -1,8 +1,10 @@
 package backend
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
 	"time"
 )
 
@@ -11,10 +13,23 @@ type BackupData struct {
 	Version        string                      `json:"version"`
 	Timestamp      time.Time                   `json:"timestamp"`
 	Users          []User                      `json:"users"`
+	Projects       []Project                   `json:"projects"`
+	Tags           []Tag                       `json:"tags"`
+	TaskTags       []TaskTag                   `json:"task_tags"`
+	SessionTags    []SessionTag                `json:"session_tags"`
+	WorkflowStates []WorkflowState             `json:"workflow_states"`
 	Tasks          []Task                      `json:"tasks"`
+	Transitions    []TaskTransition            `json:"task_transitions"`
+	Dependencies   []TaskDependency            `json:"task_dependencies"`
+	TaskEvents     []TaskEvent                 `json:"task_events"`
+	Templates      []TaskTemplate              `json:"task_templates"`
+	Attachments    []Attachment                `json:"task_attachments"`
+	PurgedTasks    []PurgedTask                `json:"purged_tasks"`
 	Sessions       []PomodoroSession           `json:"pomodoro_sessions"` // Renamed from PomodoroSessions to match likely JSON key preference or keep simple
 	DailyRetros    []DailyRetro                `json:"daily_retros"`
 	WaterReminders []UserWaterReminderSettings `json:"water_reminders"`
+
+	Files []AttachmentFile `json:"attachment_files,omitempty"` // Only in backups that include attachments
 }
 
 // UserWaterReminderSettings wraps settings with user ID for export
@@ -23,8 +38,9 @@ type UserWaterReminderSettings struct {
 	Settings WaterReminderSettings `json:"settings"`
 }
 
-// ExportJSON exports all data to a JSON byte slice
-func (s *Storage) ExportJSON() ([]byte, error) {
+// ExportJSON exports all data to a JSON byte slice. The content of attached files is
+// only included when includeAttachments is set, since it can make the backup much larger.
+func (s *Storage) ExportJSON(includeAttachments bool) ([]byte, error) {
 	backup := BackupData{
 		Version:   "1.0",
 		Timestamp: time.Now(),
@@ -39,13 +55,82 @@ func (s *Storage) ExportJSON() ([]byte, error) {
 
 	// 2. Get data for each user
 	for _, user := range users {
+		// Projects
+		projects, err := s.GetProjects(user.ID, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get projects for user %d: %v", user.ID, err)
+		}
+		backup.Projects = append(backup.Projects, projects...)
+
+		// Tags
+		tags, err := s.GetTags(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tags for user %d: %v", user.ID, err)
+		}
+		backup.Tags = append(backup.Tags, tags...)
+
+		taskTags, err := s.getTaskTagLinks(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get task tags for user %d: %v", user.ID, err)
+		}
+		backup.TaskTags = append(backup.TaskTags, taskTags...)
+
+		sessionTags, err := s.getSessionTagLinks(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get session tags for user %d: %v", user.ID, err)
+		}
+		backup.SessionTags = append(backup.SessionTags, sessionTags...)
+
+		// Workflow
+		states, err := s.getStoredWorkflowStates(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get workflow states for user %d: %v", user.ID, err)
+		}
+		backup.WorkflowStates = append(backup.WorkflowStates, states...)
+
 		// Tasks
-		tasks, err := s.GetTasks(user.ID)
+		tasks, err := s.getAllTasksForUser(user.ID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get tasks for user %d: %v", user.ID, err)
 		}
 		backup.Tasks = append(backup.Tasks, tasks...)
 
+		transitions, err := s.getAllTransitionsForUser(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get task transitions for user %d: %v", user.ID, err)
+		}
+		backup.Transitions = append(backup.Transitions, transitions...)
+
+		dependencies, err := s.getTaskDependencies(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get task dependencies for user %d: %v", user.ID, err)
+		}
+		backup.Dependencies = append(backup.Dependencies, dependencies...)
+
+		events, err := s.getAllTaskEventsForUser(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get task events for user %d: %v", user.ID, err)
+		}
+		backup.TaskEvents = append(backup.TaskEvents, events...)
+
+		templates, err := s.GetTemplates(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get templates for user %d: %v", user.ID, err)
+		}
+		backup.Templates = append(backup.Templates, templates...)
+
+		attachments, err := s.getAllAttachmentsForUser(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get attachments for user %d: %v", user.ID, err)
+		}
+		backup.Attachments = append(backup.Attachments, attachments...)
+
+		purged, err := s.getPurgedTasks(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get purged tasks for user %d: %v", user.ID, err)
+		}
+		backup.PurgedTasks = append(backup.PurgedTasks, purged...)
+
 		// Pomodoro Sessions
 		// We need all sessions, GetSessions filters by date.
 		// Let's add a helper or just query raw here.
@@ -76,9 +161,48 @@ func (s *Storage) ExportJSON() ([]byte, error) {
 		})
 	}
 
+	if includeAttachments {
+		files, err := readAttachmentFiles(backup.Attachments)
+		if err != nil {
+			return nil, err
+		}
+		backup.Files = files
+	}
+
 	return json.MarshalIndent(backup, "", "  ")
 }
 
+// readAttachmentFiles reads the stored content of the given attachments, once per file
+func readAttachmentFiles(attachments []Attachment) ([]AttachmentFile, error) {
+	dir, err := GetAttachmentsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []AttachmentFile
+	seen := make(map[string]bool)
+	for _, attachment := range attachments {
+		if seen[attachment.Hash] {
+			continue
+		}
+		seen[attachment.Hash] = true
+
+		path, err := attachmentPath(dir, attachment.Hash)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue // Nothing to back up, the attachment is already broken
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attachment %s: %v", attachment.Name, err)
+		}
+		files = append(files, AttachmentFile{Hash: attachment.Hash, Data: data})
+	}
+	return files, nil
+}
+
 // ImportJSON imports data from a JSON byte slice
 func (s *Storage) ImportJSON(data []byte) error {
 	var backup BackupData
@@ -86,6 +210,23 @@ func (s *Storage) ImportJSON(data []byte) error {
 		return fmt.Errorf("failed to parse backup data: %v", err)
 	}
 
+	// Restore attachment files first; they are only used once their metadata is restored
+	if len(backup.Files) > 0 {
+		dir, err := GetAttachmentsDir()
+		if err != nil {
+			return err
+		}
+		for _, file := range backup.Files {
+			hash, _, err := storeAttachment(dir, bytes.NewReader(file.Data))
+			if err != nil {
+				return fmt.Errorf("failed to restore attachment file %s: %v", file.Hash, err)
+			}
+			if hash != file.Hash {
+				return fmt.Errorf("attachment file %s is corrupted", file.Hash)
+			}
+		}
+	}
+
 	// Begin transaction
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -106,8 +247,37 @@ func (s *Storage) ImportJSON(data []byte) error {
 		}
 	}
 
+	// Restore Projects
+	stmtProject, err := tx.Prepare(`INSERT OR REPLACE INTO projects (id, user_id, name, color, archived, created_at, archived_at) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmtProject.Close()
+	for _, p := range backup.Projects {
+		var archivedAt interface{}
+		if p.ArchivedAt != nil {
+			archivedAt = p.ArchivedAt.Format(time.RFC3339)
+		}
+		_, err = stmtProject.Exec(p.ID, p.UserID, p.Name, p.Color, p.Archived, p.CreatedAt.Format(time.RFC3339), archivedAt)
+		if err != nil {
+			return fmt.Errorf("failed to restore project %d: %v", p.ID, err)
+		}
+	}
+
+	// Restore Workflow States
+	stmtState, err := tx.Prepare(`INSERT OR REPLACE INTO workflow_states (id, user_id, name, position, terminal) VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmtState.Close()
+	for _, ws := range backup.WorkflowStates {
+		if _, err = stmtState.Exec(ws.ID, ws.UserID, ws.Name, ws.Position, ws.Terminal); err != nil {
+			return fmt.Errorf("failed to restore workflow state %s: %v", ws.Name, err)
+		}
+	}
+
 	// Restore Tasks
-	stmtTask, err := tx.Prepare(`INSERT OR REPLACE INTO tasks (id, user_id, title, description, completed, created_at, completed_at) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	stmtTask, err := tx.Prepare(`INSERT OR REPLACE INTO tasks (id, user_id, title, description, completed, created_at, completed_at, project_id, parent_id, auto_complete, estimated_pomodoros, due_date, priority, sort_rank, recurrence, occurrence, deleted_at, state) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return err
 	}
@@ -117,23 +287,138 @@ func (s *Storage) ImportJSON(data []byte) error {
 		if t.CompletedAt != nil {
 			completedAt = t.CompletedAt.Format(time.RFC3339)
 		}
-		_, err = stmtTask.Exec(t.ID, t.UserID, t.Title, t.Description, t.Completed, t.CreatedAt.Format(time.RFC3339), completedAt)
+		var deletedAt interface{}
+		if t.DeletedAt != nil {
+			deletedAt = t.DeletedAt.Format(time.RFC3339)
+		}
+		_, err = stmtTask.Exec(t.ID, t.UserID, t.Title, t.Description, t.Completed, t.CreatedAt.Format(time.RFC3339), completedAt, t.ProjectID, t.ParentID, t.AutoComplete, t.EstimatedPomodoros,
+			nullableString(t.DueDate), t.Priority, t.SortRank, nullableString(t.Recurrence), max(t.Occurrence, 1), deletedAt, nullableString(t.State))
 		if err != nil {
 			return fmt.Errorf("failed to restore task %d: %v", t.ID, err)
 		}
 	}
 
+	// Backups from before workflow states only know whether a task is completed
+	if err := fillTaskStates(tx); err != nil {
+		return fmt.Errorf("failed to fill task states: %v", err)
+	}
+
+	stmtTransition, err := tx.Prepare(`INSERT OR REPLACE INTO task_transitions (id, user_id, task_id, from_state, to_state, moved_at) VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmtTransition.Close()
+	for _, tr := range backup.Transitions {
+		_, err = stmtTransition.Exec(tr.ID, tr.UserID, tr.TaskID, nullableString(tr.FromState), tr.ToState, tr.MovedAt.Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("failed to restore transition %d: %v", tr.ID, err)
+		}
+	}
+
+	stmtDependency, err := tx.Prepare(`INSERT OR IGNORE INTO task_dependencies (task_id, blocked_by_id) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmtDependency.Close()
+	for _, td := range backup.Dependencies {
+		if _, err = stmtDependency.Exec(td.TaskID, td.BlockedByID); err != nil {
+			return fmt.Errorf("failed to restore dependency of task %d: %v", td.TaskID, err)
+		}
+	}
+
+	for _, te := range backup.TaskEvents {
+		if err := insertTaskEvent(tx, &te); err != nil {
+			return fmt.Errorf("failed to restore task event %d: %v", te.ID, err)
+		}
+	}
+
 	// Restore Pomodoro Sessions
-	stmtSession, err := tx.Prepare(`INSERT OR REPLACE INTO pomodoro_sessions (id, user_id, task_id, duration, started_at, completed_at) VALUES (?, ?, ?, ?, ?, ?)`)
+	stmtSession, err := tx.Prepare(`INSERT OR REPLACE INTO pomodoro_sessions (id, user_id, task_id, duration, started_at, completed_at, notes, paused_seconds, mode, outcome, reason) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return err
 	}
 	defer stmtSession.Close()
 	for _, ps := range backup.Sessions {
-		_, err = stmtSession.Exec(ps.ID, ps.UserID, ps.TaskID, ps.Duration, ps.StartedAt, ps.CompletedAt)
+		_, err = stmtSession.Exec(ps.ID, ps.UserID, ps.TaskID, ps.Duration, ps.StartedAt, ps.CompletedAt, nullableString(ps.Notes), ps.PausedSeconds, sessionMode(ps.Mode), sessionOutcome(ps.Outcome), nullableString(ps.Reason))
 		if err != nil {
 			return fmt.Errorf("failed to restore session %d: %v", ps.ID, err)
 		}
+		// The pauses of a session already in the database are replaced, not added to
+		if err := deleteSessionSegments(tx, ps.ID); err != nil {
+			return fmt.Errorf("failed to restore pauses of session %d: %v", ps.ID, err)
+		}
+		if err := insertSessionSegments(tx, &ps); err != nil {
+			return fmt.Errorf("failed to restore pauses of session %d: %v", ps.ID, err)
+		}
+	}
+
+	// Restore Tags
+	stmtTag, err := tx.Prepare(`INSERT OR REPLACE INTO tags (id, user_id, name, created_at) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmtTag.Close()
+	for _, t := range backup.Tags {
+		_, err = stmtTag.Exec(t.ID, t.UserID, t.Name, t.CreatedAt.Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("failed to restore tag %s: %v", t.Name, err)
+		}
+	}
+
+	stmtTaskTag, err := tx.Prepare(`INSERT OR IGNORE INTO task_tags (task_id, tag_id) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmtTaskTag.Close()
+	for _, tt := range backup.TaskTags {
+		if _, err = stmtTaskTag.Exec(tt.TaskID, tt.TagID); err != nil {
+			return fmt.Errorf("failed to restore tag of task %d: %v", tt.TaskID, err)
+		}
+	}
+
+	stmtSessionTag, err := tx.Prepare(`INSERT OR IGNORE INTO session_tags (session_id, tag_id) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmtSessionTag.Close()
+	for _, st := range backup.SessionTags {
+		if _, err = stmtSessionTag.Exec(st.SessionID, st.TagID); err != nil {
+			return fmt.Errorf("failed to restore tag of session %d: %v", st.SessionID, err)
+		}
+	}
+
+	// Restore Task Templates
+	for _, tt := range backup.Templates {
+		tags, err := json.Marshal(tt.Tags)
+		if err != nil {
+			return err
+		}
+		checklist, err := json.Marshal(tt.Checklist)
+		if err != nil {
+			return err
+		}
+		if err := saveTemplate(tx, &tt, string(tags), string(checklist)); err != nil {
+			return fmt.Errorf("failed to restore template %d: %v", tt.ID, err)
+		}
+	}
+
+	// Restore Attachments
+	for _, ta := range backup.Attachments {
+		if err := insertAttachment(tx, &ta); err != nil {
+			return fmt.Errorf("failed to restore attachment %d: %v", ta.ID, err)
+		}
+	}
+
+	// Restore Purged Tasks
+	stmtPurged, err := tx.Prepare(`INSERT OR REPLACE INTO purged_tasks (id, user_id, title, purged_at) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmtPurged.Close()
+	for _, pt := range backup.PurgedTasks {
+		if _, err = stmtPurged.Exec(pt.ID, pt.UserID, pt.Title, pt.PurgedAt.Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("failed to restore purged task %d: %v", pt.ID, err)
+		}
 	}
 
 	// Restore Daily Retros
@@ -162,6 +447,11 @@ func (s *Storage) ImportJSON(data []byte) error {
 		}
 	}
 
+	// The restored rows bypass the regular write paths, so index them from scratch
+	if err := rebuildSearchIndex(tx); err != nil {
+		return fmt.Errorf("failed to rebuild search index: %v", err)
+	}
+
 	return tx.Commit()
 }
 
@@ -186,23 +476,23 @@ func (s *Storage) getAllUsers() ([]User, error) {
 	return users, nil
 }
 
-func (s *Storage) getAllSessionsForUser(userID int64) ([]PomodoroSession, error) {
-	query := `SELECT id, user_id, task_id, duration, started_at, completed_at FROM pomodoro_sessions WHERE user_id = ?`
-	rows, err := s.db.Query(query, userID)
+// getAllTasksForUser returns every task of a user, including those in the trash
+func (s *Storage) getAllTasksForUser(userID int64) ([]Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE user_id = ?`
+	tasks, err := s.queryTasks(query, userID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return tasks, s.attachTaskTags(userID, tasks)
+}
 
-	var sessions []PomodoroSession
-	for rows.Next() {
-		var s PomodoroSession
-		if err := rows.Scan(&s.ID, &s.UserID, &s.TaskID, &s.Duration, &s.StartedAt, &s.CompletedAt); err != nil {
-			return nil, err
-		}
-		sessions = append(sessions, s)
+func (s *Storage) getAllSessionsForUser(userID int64) ([]PomodoroSession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM pomodoro_sessions WHERE user_id = ?`
+	sessions, err := s.querySessions(query, userID)
+	if err != nil {
+		return nil, err
 	}
-	return sessions, nil
+	return sessions, s.attachSessionSegments(userID, sessions)
 }
 
 func (s *Storage) getAllRetrosForUser(userID int64) ([]DailyRetro, error) {
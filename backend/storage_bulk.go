@@ -0,0 +1,173 @@
+package backend
+
+import (
+	"database/sql"
+	"time"
+)
+
+// runTaskBatch applies an operation to each of the given tasks in a single transaction. Every
+// task gets its own savepoint, so a task that fails is rolled back and reported on its own
+// while the others are still committed. Operations roll their changes up to the parent
+// tasks under the same savepoint, so a task and its roll-ups stand or fall together.
+func (s *Storage) runTaskBatch(taskIDs []int64, apply func(tx *sql.Tx, taskID int64) error) ([]BulkResult, error) {
+	return s.runPreparedTaskBatch(taskIDs, nil, apply)
+}
+
+// runPreparedTaskBatch is runTaskBatch with a step run first in the same transaction, such
+// as creating rows that every task refers to. The batch fails as a whole if that step fails.
+func (s *Storage) runPreparedTaskBatch(taskIDs []int64, prepare func(tx *sql.Tx) error, apply func(tx *sql.Tx, taskID int64) error) ([]BulkResult, error) {
+	var results []BulkResult
+	err := retryOnBusy(func() error {
+		results = make([]BulkResult, 0, len(taskIDs))
+
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if prepare != nil {
+			if err := prepare(tx); err != nil {
+				return err
+			}
+		}
+
+		for _, taskID := range taskIDs {
+			if _, err := tx.Exec(`SAVEPOINT bulk_task`); err != nil {
+				return err
+			}
+
+			result := BulkResult{TaskID: taskID, Success: true}
+			if err := apply(tx, taskID); err != nil {
+				if _, err := tx.Exec(`ROLLBACK TO bulk_task`); err != nil {
+					return err
+				}
+				result.Success = false
+				result.Error = err.Error()
+			}
+
+			if _, err := tx.Exec(`RELEASE bulk_task`); err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return tx.Commit()
+	}, 3)
+	return results, err
+}
+
+// SetTasksCompleted completes or reopens many tasks of a user at once, like UpdateTask
+func (s *Storage) SetTasksCompleted(userID int64, taskIDs []int64, completed bool) ([]BulkResult, error) {
+	now := time.Now()
+	return s.runTaskBatch(taskIDs, func(tx *sql.Tx, taskID int64) error {
+		task, err := getTask(tx, taskID, userID)
+		if err != nil {
+			return err
+		}
+		if task.Completed == completed {
+			return nil
+		}
+
+		existing := *task
+		task.Completed = completed
+		task.CompletedAt = nil
+		if completed {
+			task.CompletedAt = &now
+		}
+		if err := updateTask(tx, task); err != nil {
+			return err
+		}
+		return applyCompletionChange(tx, &existing, completed)
+	})
+}
+
+// DeleteTasks moves many tasks of a user to the trash at once, like DeleteTask
+func (s *Storage) DeleteTasks(userID int64, taskIDs []int64) ([]BulkResult, error) {
+	return s.runTaskBatch(taskIDs, func(tx *sql.Tx, taskID int64) error {
+		task, err := getTask(tx, taskID, userID)
+		if err != nil {
+			return err
+		}
+		if err := deleteTask(tx, taskID, userID); err != nil {
+			return err
+		}
+
+		// The parent may be complete now, unless it is in the trash as well
+		if task.ParentID == nil {
+			return nil
+		}
+		if _, err := getTask(tx, *task.ParentID, userID); err != nil {
+			return nil
+		}
+		return rollUpCompletion(tx, *task.ParentID, userID)
+	})
+}
+
+// RetagTasks adds and removes tags on many tasks of a user at once. Missing tags to add are
+// created in the same transaction.
+func (s *Storage) RetagTasks(userID int64, taskIDs []int64, addTags, removeTags []string) ([]BulkResult, error) {
+	var addTagIDs, removeTagIDs []int64
+	prepare := func(tx *sql.Tx) error {
+		addTagIDs, removeTagIDs = nil, nil
+		for _, name := range addTags {
+			tag, err := getOrCreateTag(tx, userID, name)
+			if err != nil {
+				return err
+			}
+			addTagIDs = append(addTagIDs, tag.ID)
+		}
+		for _, name := range removeTags {
+			ids, err := queryIDs(tx, `SELECT id FROM tags WHERE user_id = ? AND name = ?`, userID, name)
+			if err != nil {
+				return err
+			}
+			removeTagIDs = append(removeTagIDs, ids...)
+		}
+		return nil
+	}
+
+	return s.runPreparedTaskBatch(taskIDs, prepare, func(tx *sql.Tx, taskID int64) error {
+		if _, err := getTask(tx, taskID, userID); err != nil {
+			return err
+		}
+		for _, tagID := range removeTagIDs {
+			if err := removeTaskTag(tx, taskID, tagID); err != nil {
+				return err
+			}
+		}
+		for _, tagID := range addTagIDs {
+			if err := addTaskTag(tx, taskID, tagID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MoveTasks moves many tasks of a user to a workflow state at once, like MoveTask
+func (s *Storage) MoveTasks(userID int64, taskIDs []int64, state WorkflowState) ([]BulkResult, error) {
+	now := time.Now()
+	return s.runTaskBatch(taskIDs, func(tx *sql.Tx, taskID int64) error {
+		task, err := getTask(tx, taskID, userID)
+		if err != nil {
+			return err
+		}
+		if task.State == state.Name {
+			return nil
+		}
+
+		existing := *task
+		task.State = state.Name
+		if state.Terminal != task.Completed {
+			task.Completed = state.Terminal
+			task.CompletedAt = nil
+			if state.Terminal {
+				task.CompletedAt = &now
+			}
+		}
+		if err := updateTask(tx, task); err != nil {
+			return err
+		}
+		return applyCompletionChange(tx, &existing, task.Completed)
+	})
+}
@@ -0,0 +1,95 @@
+package backend
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UpdateTaskAndRollUp updates a task like UpdateTask, then rolls a change in its completion
+// up to its parent and schedules its next occurrence in the same transaction. existing is
+// the task as it was before the update.
+func (s *Storage) UpdateTaskAndRollUp(task, existing *Task) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := updateTask(tx, task); err != nil {
+			return err
+		}
+		if err := applyCompletionChange(tx, existing, task.Completed); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// applyCompletionChange rolls a change in the completion of a task up to its parent, and
+// schedules the next occurrence when an occurrence of a recurring task was completed.
+// existing is the task as it was before the change.
+func applyCompletionChange(tx *sql.Tx, existing *Task, completed bool) error {
+	if completed == existing.Completed {
+		return nil
+	}
+
+	// The next occurrence goes under the same parent, so it is spawned first to keep the
+	// parent open
+	if completed && existing.Recurrence != "" {
+		if _, err := spawnNextOccurrence(tx, existing); err != nil {
+			return err
+		}
+	}
+
+	if existing.ParentID != nil {
+		return rollUpCompletion(tx, *existing.ParentID, existing.UserID)
+	}
+	return nil
+}
+
+// rollUpCompletion completes an auto-completing task when all of its subtasks are
+// completed, or reopens it when one of them is open again, then repeats for its parent
+func rollUpCompletion(tx *sql.Tx, taskID, userID int64) error {
+	task, err := getTask(tx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if !task.AutoComplete {
+		return nil
+	}
+
+	children, err := getSubtasks(tx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if len(children) == 0 {
+		return nil
+	}
+
+	allCompleted := true
+	for _, child := range children {
+		if !child.Completed {
+			allCompleted = false
+			break
+		}
+	}
+	if allCompleted == task.Completed {
+		return nil
+	}
+
+	task.Completed = allCompleted
+	task.CompletedAt = nil
+	if allCompleted {
+		now := time.Now()
+		task.CompletedAt = &now
+	}
+	if err := updateTask(tx, task); err != nil {
+		return err
+	}
+
+	if task.ParentID != nil {
+		return rollUpCompletion(tx, *task.ParentID, userID)
+	}
+	return nil
+}
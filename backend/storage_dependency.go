@@ -0,0 +1,108 @@
+package backend
+
+// AddTaskDependency records that a task is blocked by another task
+func (s *Storage) AddTaskDependency(taskID, blockedByID int64) error {
+	return retryOnBusy(func() error {
+		query := `INSERT OR IGNORE INTO task_dependencies (task_id, blocked_by_id) VALUES (?, ?)`
+		_, err := s.db.Exec(query, taskID, blockedByID)
+		return err
+	}, 3)
+}
+
+// RemoveTaskDependency removes a blocked-by edge between two tasks
+func (s *Storage) RemoveTaskDependency(taskID, blockedByID int64) error {
+	return retryOnBusy(func() error {
+		query := `DELETE FROM task_dependencies WHERE task_id = ? AND blocked_by_id = ?`
+		_, err := s.db.Exec(query, taskID, blockedByID)
+		return err
+	}, 3)
+}
+
+// getTaskBlockers returns the IDs of the tasks blocking each task of a user, keyed by
+// task ID. Blockers in the trash are left out, as they no longer block anything.
+func (s *Storage) getTaskBlockers(userID int64) (map[int64][]int64, error) {
+	query := `SELECT d.task_id, d.blocked_by_id FROM task_dependencies d
+	          JOIN tasks b ON b.id = d.blocked_by_id
+	          WHERE b.user_id = ? AND b.deleted_at IS NULL`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blockers := make(map[int64][]int64)
+	for rows.Next() {
+		var taskID, blockedByID int64
+		if err := rows.Scan(&taskID, &blockedByID); err != nil {
+			return nil, err
+		}
+		blockers[taskID] = append(blockers[taskID], blockedByID)
+	}
+	return blockers, rows.Err()
+}
+
+// attachTaskDependencies fills in the BlockedBy and Blocked fields of each task. A task is
+// blocked while any of the tasks blocking it is not completed.
+func (s *Storage) attachTaskDependencies(userID int64, tasks []Task) error {
+	query := `SELECT d.task_id, d.blocked_by_id, b.completed FROM task_dependencies d
+	          JOIN tasks b ON b.id = d.blocked_by_id
+	          WHERE b.user_id = ? AND b.deleted_at IS NULL`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	blockedBy := make(map[int64][]int64)
+	blocked := make(map[int64]bool)
+	for rows.Next() {
+		var taskID, blockedByID int64
+		var completed bool
+		if err := rows.Scan(&taskID, &blockedByID, &completed); err != nil {
+			return err
+		}
+		blockedBy[taskID] = append(blockedBy[taskID], blockedByID)
+		if !completed {
+			blocked[taskID] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		tasks[i].BlockedBy = blockedBy[tasks[i].ID]
+		tasks[i].Blocked = blocked[tasks[i].ID]
+	}
+	return nil
+}
+
+// GetOpenBlockers retrieves the tasks that still block a task, i.e. are not completed
+func (s *Storage) GetOpenBlockers(taskID, userID int64) ([]Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks 
+	          WHERE id IN (SELECT blocked_by_id FROM task_dependencies WHERE task_id = ?)
+	          AND user_id = ? AND completed = 0 AND deleted_at IS NULL
+	          ORDER BY sort_rank, created_at DESC`
+	return s.queryTasks(query, taskID, userID)
+}
+
+// getTaskDependencies returns every blocked-by edge of a user for export
+func (s *Storage) getTaskDependencies(userID int64) ([]TaskDependency, error) {
+	query := `SELECT d.task_id, d.blocked_by_id FROM task_dependencies d
+	          JOIN tasks t ON t.id = d.task_id WHERE t.user_id = ?`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dependencies []TaskDependency
+	for rows.Next() {
+		var dependency TaskDependency
+		if err := rows.Scan(&dependency.TaskID, &dependency.BlockedByID); err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, rows.Err()
+}
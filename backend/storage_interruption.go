@@ -0,0 +1,127 @@
+package backend
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// insertSessionSegments stores the pause segments and interruptions of a Pomodoro session
+func insertSessionSegments(db execer, session *PomodoroSession) error {
+	for _, pause := range session.Pauses {
+		query := `INSERT INTO session_pauses (session_id, user_id, paused_at, resumed_at) VALUES (?, ?, ?, ?)`
+		_, err := db.Exec(query, session.ID, session.UserID,
+			pause.PausedAt.Format(time.RFC3339), pause.ResumedAt.Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+	}
+	for _, interruption := range session.Interruptions {
+		query := `INSERT OR REPLACE INTO session_interruptions (id, user_id, session_id, kind, note, occurred_at)
+		          VALUES (?, ?, ?, ?, ?, ?)`
+		_, err := db.Exec(query, interruption.ID, session.UserID, session.ID, interruption.Kind,
+			nullableString(interruption.Note), interruption.OccurredAt.Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sessionSegmentBatch is how many sessions attachSessionSegments looks up per query, well
+// under the limit SQLite puts on the number of query parameters
+const sessionSegmentBatch = 500
+
+// attachSessionSegments fills in the Pauses and Interruptions fields of each session
+func (s *Storage) attachSessionSegments(userID int64, sessions []PomodoroSession) error {
+	for start := 0; start < len(sessions); start += sessionSegmentBatch {
+		batch := sessions[start:min(start+sessionSegmentBatch, len(sessions))]
+		ids := make([]interface{}, len(batch))
+		for i, session := range batch {
+			ids[i] = session.ID
+		}
+
+		pauses, err := s.getSessionPauses(userID, ids)
+		if err != nil {
+			return err
+		}
+		interruptions, err := s.getSessionInterruptions(userID, ids)
+		if err != nil {
+			return err
+		}
+		for i := range batch {
+			batch[i].Pauses = pauses[batch[i].ID]
+			batch[i].Interruptions = interruptions[batch[i].ID]
+		}
+	}
+	return nil
+}
+
+// sessionIDClause builds a condition matching rows whose session_id is one of ids, with the
+// user ID as the first of its arguments
+func sessionIDClause(userID int64, ids []interface{}) (string, []interface{}) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	return `user_id = ? AND session_id IN (` + placeholders + `)`, append([]interface{}{userID}, ids...)
+}
+
+// getSessionPauses returns the pause segments of the given sessions of a user, keyed by
+// session ID
+func (s *Storage) getSessionPauses(userID int64, sessionIDs []interface{}) (map[int64][]PauseSegment, error) {
+	clause, args := sessionIDClause(userID, sessionIDs)
+	query := `SELECT session_id, paused_at, resumed_at FROM session_pauses
+	          WHERE ` + clause + ` ORDER BY paused_at, id`
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pauses := make(map[int64][]PauseSegment)
+	for rows.Next() {
+		var sessionID int64
+		var pausedAtStr, resumedAtStr string
+		if err := rows.Scan(&sessionID, &pausedAtStr, &resumedAtStr); err != nil {
+			return nil, err
+		}
+		var pause PauseSegment
+		if pause.PausedAt, err = time.Parse(time.RFC3339, pausedAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse paused_at: %v", err)
+		}
+		if pause.ResumedAt, err = time.Parse(time.RFC3339, resumedAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse resumed_at: %v", err)
+		}
+		pauses[sessionID] = append(pauses[sessionID], pause)
+	}
+	return pauses, rows.Err()
+}
+
+// getSessionInterruptions returns the interruptions of the given sessions of a user, keyed
+// by session ID
+func (s *Storage) getSessionInterruptions(userID int64, sessionIDs []interface{}) (map[int64][]Interruption, error) {
+	clause, args := sessionIDClause(userID, sessionIDs)
+	query := `SELECT id, user_id, session_id, kind, note, occurred_at FROM session_interruptions
+	          WHERE ` + clause + ` ORDER BY occurred_at`
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	interruptions := make(map[int64][]Interruption)
+	for rows.Next() {
+		var interruption Interruption
+		var note sql.NullString
+		var occurredAtStr string
+		if err := rows.Scan(&interruption.ID, &interruption.UserID, &interruption.SessionID,
+			&interruption.Kind, &note, &occurredAtStr); err != nil {
+			return nil, err
+		}
+		interruption.Note = note.String
+		if interruption.OccurredAt, err = time.Parse(time.RFC3339, occurredAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse occurred_at: %v", err)
+		}
+		interruptions[interruption.SessionID] = append(interruptions[interruption.SessionID], interruption)
+	}
+	return interruptions, rows.Err()
+}
@@ -0,0 +1,101 @@
+package backend
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CreateProject creates a new project
+func (s *Storage) CreateProject(project *Project) error {
+	return retryOnBusy(func() error {
+		query := `INSERT INTO projects (id, user_id, name, color, archived, created_at)
+				  VALUES (?, ?, ?, ?, ?, ?)`
+		_, err := s.db.Exec(query, project.ID, project.UserID, project.Name, project.Color,
+			project.Archived, project.CreatedAt.Format(time.RFC3339))
+		return err
+	}, 3)
+}
+
+// GetProjects retrieves the projects of a user, optionally including archived ones
+func (s *Storage) GetProjects(userID int64, includeArchived bool) ([]Project, error) {
+	query := `SELECT id, user_id, name, color, archived, created_at, archived_at
+	          FROM projects WHERE user_id = ?`
+	if !includeArchived {
+		query += ` AND archived = 0`
+	}
+	query += ` ORDER BY name COLLATE NOCASE`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []Project
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, *project)
+	}
+	return projects, nil
+}
+
+// GetProject retrieves a single project owned by a user
+func (s *Storage) GetProject(projectID, userID int64) (*Project, error) {
+	query := `SELECT id, user_id, name, color, archived, created_at, archived_at
+	          FROM projects WHERE id = ? AND user_id = ?`
+	project, err := scanProject(s.db.QueryRow(query, projectID, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("project not found")
+	}
+	return project, err
+}
+
+// UpdateProject updates the name and color of a project
+func (s *Storage) UpdateProject(project *Project) error {
+	return retryOnBusy(func() error {
+		query := `UPDATE projects SET name = ?, color = ? WHERE id = ? AND user_id = ?`
+		_, err := s.db.Exec(query, project.Name, project.Color, project.ID, project.UserID)
+		return err
+	}, 3)
+}
+
+// ArchiveProject marks a project as archived
+func (s *Storage) ArchiveProject(projectID, userID int64) error {
+	return retryOnBusy(func() error {
+		query := `UPDATE projects SET archived = 1, archived_at = ? WHERE id = ? AND user_id = ?`
+		_, err := s.db.Exec(query, time.Now().Format(time.RFC3339), projectID, userID)
+		return err
+	}, 3)
+}
+
+// scanProject scans a project row
+func scanProject(row interface{ Scan(...interface{}) error }) (*Project, error) {
+	project := &Project{}
+	var createdAtStr string
+	var archivedAtStr sql.NullString
+
+	err := row.Scan(&project.ID, &project.UserID, &project.Name, &project.Color,
+		&project.Archived, &createdAtStr, &archivedAtStr)
+	if err != nil {
+		return nil, err
+	}
+
+	project.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %v", err)
+	}
+
+	if archivedAtStr.Valid {
+		t, err := time.Parse(time.RFC3339, archivedAtStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse archived_at: %v", err)
+		}
+		project.ArchivedAt = &t
+	}
+
+	return project, nil
+}
@@ -0,0 +1,65 @@
+package backend
+
+import (
+	"database/sql"
+	"time"
+)
+
+// spawnNextOccurrence creates the next occurrence of a recurring task that was just completed.
+// The rule moves to the new occurrence, so reopening and completing the old one again does
+// not spawn a duplicate. It returns nil when the series has ended.
+func spawnNextOccurrence(tx *sql.Tx, task *Task) (*Task, error) {
+	rule, err := ParseRecurrenceRule(task.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	// Occurrences are anchored on their due date, or on the creation date when there is none
+	anchor := task.CreatedAt
+	if task.DueDate != "" {
+		if anchor, err = time.ParseInLocation("2006-01-02", task.DueDate, time.Local); err != nil {
+			return nil, err
+		}
+	}
+
+	occurrence := max(task.Occurrence, 1)
+	nextDate, ok := rule.Next(anchor, occurrence)
+	if !ok {
+		return nil, updateTaskColumn(tx, task.ID, task.UserID, "recurrence", nil)
+	}
+
+	next := &Task{
+		ID:                 GenerateID(),
+		UserID:             task.UserID,
+		Title:              task.Title,
+		Description:        task.Description,
+		CreatedAt:          time.Now(),
+		ProjectID:          task.ProjectID,
+		ParentID:           task.ParentID,
+		AutoComplete:       task.AutoComplete,
+		EstimatedPomodoros: task.EstimatedPomodoros,
+		DueDate:            nextDate.Format("2006-01-02"),
+		Priority:           task.Priority,
+		Recurrence:         task.Recurrence,
+		Occurrence:         occurrence + 1,
+	}
+	if err := createTask(tx, next); err != nil {
+		return nil, err
+	}
+
+	tagIDs, err := queryIDs(tx, `SELECT tag_id FROM task_tags WHERE task_id = ?`, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tagID := range tagIDs {
+		if err := addTaskTag(tx, next.ID, tagID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := updateTaskColumn(tx, task.ID, task.UserID, "recurrence", nil); err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
@@ -0,0 +1,185 @@
+package backend
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// searchSchema holds the FTS5 tables. The rowid of each row is the ID of the indexed entity.
+const searchSchema = `
+	CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
+		title, description, user_id UNINDEXED
+	);
+
+	CREATE VIRTUAL TABLE IF NOT EXISTS retros_fts USING fts5(
+		retro_notes, plan_notes, user_id UNINDEXED, date UNINDEXED
+	);
+
+	CREATE VIRTUAL TABLE IF NOT EXISTS sessions_fts USING fts5(
+		notes, user_id UNINDEXED
+	);
+`
+
+// migrateSearchIndex creates the full-text search tables and, when they did not exist
+// yet, fills them from the existing tasks, retros and sessions
+func (s *Storage) migrateSearchIndex() error {
+	var exists bool
+	query := `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'tasks_fts'`
+	if err := s.db.QueryRow(query).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check for search index: %v", err)
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(searchSchema); err != nil {
+		return fmt.Errorf("failed to create search index: %v", err)
+	}
+	if err := rebuildSearchIndex(tx); err != nil {
+		return fmt.Errorf("failed to build search index: %v", err)
+	}
+	return tx.Commit()
+}
+
+// rebuildSearchIndex replaces the contents of the search index with the current data
+func rebuildSearchIndex(db execer) error {
+	statements := []string{
+		`DELETE FROM tasks_fts`,
+		`INSERT INTO tasks_fts (rowid, title, description, user_id)
+		 SELECT id, title, COALESCE(description, ''), user_id FROM tasks`,
+		`DELETE FROM retros_fts`,
+		`INSERT INTO retros_fts (rowid, retro_notes, plan_notes, user_id, date)
+		 SELECT id, COALESCE(retro_notes, ''), COALESCE(plan_notes, ''), user_id, date FROM daily_retros`,
+		`DELETE FROM sessions_fts`,
+		`INSERT INTO sessions_fts (rowid, notes, user_id)
+		 SELECT id, notes, user_id FROM pomodoro_sessions WHERE COALESCE(notes, '') != ''`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexTask writes the searchable fields of a task to the search index
+func indexTask(db execer, task *Task) error {
+	if _, err := db.Exec(`DELETE FROM tasks_fts WHERE rowid = ?`, task.ID); err != nil {
+		return err
+	}
+	_, err := db.Exec(`INSERT INTO tasks_fts (rowid, title, description, user_id) VALUES (?, ?, ?, ?)`,
+		task.ID, task.Title, task.Description, task.UserID)
+	return err
+}
+
+// indexRetro writes the notes of a daily retro to the search index. Retros are
+// upserted by date, so the row is looked up by user and date rather than by ID.
+func indexRetro(db execer, retro *DailyRetro) error {
+	if _, err := db.Exec(`DELETE FROM retros_fts WHERE user_id = ? AND date = ?`, retro.UserID, retro.Date); err != nil {
+		return err
+	}
+	_, err := db.Exec(`INSERT INTO retros_fts (rowid, retro_notes, plan_notes, user_id, date)
+		SELECT id, COALESCE(retro_notes, ''), COALESCE(plan_notes, ''), user_id, date
+		FROM daily_retros WHERE user_id = ? AND date = ?`, retro.UserID, retro.Date)
+	return err
+}
+
+// indexSession writes the notes of a Pomodoro session to the search index
+func indexSession(db execer, session *PomodoroSession) error {
+	if _, err := db.Exec(`DELETE FROM sessions_fts WHERE rowid = ?`, session.ID); err != nil {
+		return err
+	}
+	if session.Notes == "" {
+		return nil
+	}
+	_, err := db.Exec(`INSERT INTO sessions_fts (rowid, notes, user_id) VALUES (?, ?, ?)`,
+		session.ID, session.Notes, session.UserID)
+	return err
+}
+
+// Search runs a full-text search over a user's tasks, retros and session notes
+func (s *Storage) Search(userID int64, text string) (*SearchResults, error) {
+	results := &SearchResults{
+		Tasks:    []SearchResult{},
+		Retros:   []SearchResult{},
+		Sessions: []SearchResult{},
+	}
+
+	match := buildSearchQuery(text)
+	if match == "" {
+		return results, nil
+	}
+
+	var err error
+	results.Tasks, err = s.searchIndex(SearchTypeTask, `
+		SELECT f.rowid, t.title, snippet(tasks_fts, -1, '`+snippetMarkStart+`', '`+snippetMarkEnd+`', '…', 12), '', f.rank
+		FROM tasks_fts f JOIN tasks t ON t.id = f.rowid
+		WHERE tasks_fts MATCH ? AND f.user_id = ? AND t.deleted_at IS NULL
+		ORDER BY f.rank LIMIT ?`, match, userID, searchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tasks: %v", err)
+	}
+
+	results.Retros, err = s.searchIndex(SearchTypeRetro, `
+		SELECT f.rowid, f.date, snippet(retros_fts, -1, '`+snippetMarkStart+`', '`+snippetMarkEnd+`', '…', 12), f.date, f.rank
+		FROM retros_fts f
+		WHERE retros_fts MATCH ? AND f.user_id = ?
+		ORDER BY f.rank LIMIT ?`, match, userID, searchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search retros: %v", err)
+	}
+
+	results.Sessions, err = s.searchIndex(SearchTypeSession, `
+		SELECT f.rowid, COALESCE(t.title, pt.title, ''), snippet(sessions_fts, 0, '`+snippetMarkStart+`', '`+snippetMarkEnd+`', '…', 12),
+		       ps.completed_at, f.rank
+		FROM sessions_fts f
+		JOIN pomodoro_sessions ps ON ps.id = f.rowid
+		LEFT JOIN tasks t ON t.id = ps.task_id
+		LEFT JOIN purged_tasks pt ON pt.id = ps.task_id
+		WHERE sessions_fts MATCH ? AND f.user_id = ?
+		ORDER BY f.rank LIMIT ?`, match, userID, searchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search sessions: %v", err)
+	}
+
+	return results, nil
+}
+
+// searchIndex runs a search query returning (id, title, snippet, date, rank) rows
+func (s *Storage) searchIndex(entityType, query string, args ...interface{}) ([]SearchResult, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		result := SearchResult{Type: entityType}
+		var date interface{}
+		if err := rows.Scan(&result.ID, &result.Title, &result.Snippet, &date, &result.Rank); err != nil {
+			return nil, err
+		}
+		result.Snippet = snippetHTML(result.Snippet)
+		switch d := date.(type) {
+		case time.Time:
+			result.Date = d.Format("2006-01-02")
+		case string:
+			result.Date = d
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,153 @@
+package backend
+
+import (
+	"time"
+)
+
+// updateSession writes the edited fields of a Pomodoro session and replaces its pauses and
+// interruptions
+func updateSession(db execer, session *PomodoroSession) error {
+	query := `UPDATE pomodoro_sessions SET task_id = ?, duration = ?, started_at = ?, completed_at = ?,
+	          notes = ?, paused_seconds = ?, mode = ?, outcome = ?, reason = ? WHERE id = ? AND user_id = ?`
+	_, err := db.Exec(query, session.TaskID, session.Duration, session.StartedAt, session.CompletedAt,
+		nullableString(session.Notes), session.PausedSeconds, sessionMode(session.Mode),
+		sessionOutcome(session.Outcome), nullableString(session.Reason), session.ID, session.UserID)
+	if err != nil {
+		return err
+	}
+	if err := deleteSessionSegments(db, session.ID); err != nil {
+		return err
+	}
+	if err := insertSessionSegments(db, session); err != nil {
+		return err
+	}
+	return indexSession(db, session)
+}
+
+// deleteSessionSegments removes the pauses and interruptions of a Pomodoro session
+func deleteSessionSegments(db execer, sessionID int64) error {
+	if _, err := db.Exec(`DELETE FROM session_pauses WHERE session_id = ?`, sessionID); err != nil {
+		return err
+	}
+	_, err := db.Exec(`DELETE FROM session_interruptions WHERE session_id = ?`, sessionID)
+	return err
+}
+
+// deleteSession removes a Pomodoro session of a user with its tags, pauses, interruptions
+// and search entry
+func deleteSession(db execer, sessionID, userID int64) error {
+	statements := []string{
+		`DELETE FROM session_tags WHERE session_id IN (SELECT id FROM pomodoro_sessions WHERE id = ? AND user_id = ?)`,
+		`DELETE FROM session_pauses WHERE session_id IN (SELECT id FROM pomodoro_sessions WHERE id = ? AND user_id = ?)`,
+		`DELETE FROM session_interruptions WHERE session_id IN (SELECT id FROM pomodoro_sessions WHERE id = ? AND user_id = ?)`,
+		`DELETE FROM sessions_fts WHERE rowid IN (SELECT id FROM pomodoro_sessions WHERE id = ? AND user_id = ?)`,
+		`DELETE FROM pomodoro_sessions WHERE id = ? AND user_id = ?`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement, sessionID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdatePomodoroSession saves an edited Pomodoro session
+func (s *Storage) UpdatePomodoroSession(session *PomodoroSession) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := updateSession(tx, session); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// SplitPomodoroSession saves a session cut in two. first keeps the ID of the original
+// session; second is new and gets the same tags.
+func (s *Storage) SplitPomodoroSession(first, second *PomodoroSession) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := updateSession(tx, first); err != nil {
+			return err
+		}
+		if err := insertSession(tx, second); err != nil {
+			return err
+		}
+		query := `INSERT OR IGNORE INTO session_tags (session_id, tag_id)
+		          SELECT ?, tag_id FROM session_tags WHERE session_id = ?`
+		if _, err := tx.Exec(query, second.ID, first.ID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// MergePomodoroSessions saves merged, the result of joining a session with the one whose
+// ID is removedID, and deletes the latter. The tags of both sessions are kept.
+func (s *Storage) MergePomodoroSessions(merged *PomodoroSession, removedID int64) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := updateSession(tx, merged); err != nil {
+			return err
+		}
+		query := `INSERT OR IGNORE INTO session_tags (session_id, tag_id)
+		          SELECT ?, tag_id FROM session_tags WHERE session_id = ?`
+		if _, err := tx.Exec(query, merged.ID, removedID); err != nil {
+			return err
+		}
+		if err := deleteSession(tx, removedID, merged.UserID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// DeletePomodoroSession deletes a Pomodoro session of a user
+func (s *Storage) DeletePomodoroSession(sessionID, userID int64) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := deleteSession(tx, sessionID, userID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// GetOverlappingSessions retrieves the sessions of a user that share some time with the
+// range from start to end, earliest first
+func (s *Storage) GetOverlappingSessions(userID int64, start, end time.Time) ([]PomodoroSession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM pomodoro_sessions
+	          WHERE user_id = ? AND completed_at > ? ORDER BY started_at`
+	sessions, err := s.querySessions(query, userID, start)
+	if err != nil {
+		return nil, err
+	}
+
+	overlapping := []PomodoroSession{}
+	for _, session := range sessions {
+		if session.StartedAt.Before(end) && session.CompletedAt.After(start) {
+			overlapping = append(overlapping, session)
+		}
+	}
+	return overlapping, nil
+}
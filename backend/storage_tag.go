@@ -0,0 +1,223 @@
+package backend
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GetOrCreateTag returns the user's tag with the given name, creating it if needed
+func (s *Storage) GetOrCreateTag(userID int64, name string) (*Tag, error) {
+	var tag *Tag
+	err := retryOnBusy(func() error {
+		var err error
+		tag, err = getOrCreateTag(s.db, userID, name)
+		return err
+	}, 3)
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// getOrCreateTag is GetOrCreateTag on either the database or a transaction
+func getOrCreateTag(db interface {
+	queryer
+	execer
+}, userID int64, name string) (*Tag, error) {
+	tag := &Tag{}
+	var createdAtStr string
+	query := `SELECT id, user_id, name, created_at FROM tags WHERE user_id = ? AND name = ?`
+	err := db.QueryRow(query, userID, name).Scan(&tag.ID, &tag.UserID, &tag.Name, &createdAtStr)
+	if err == nil {
+		tag.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %v", err)
+		}
+		return tag, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	tag = &Tag{
+		ID:        GenerateID(),
+		UserID:    userID,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	query = `INSERT INTO tags (id, user_id, name, created_at) VALUES (?, ?, ?, ?)`
+	if _, err := db.Exec(query, tag.ID, tag.UserID, tag.Name, tag.CreatedAt.Format(time.RFC3339)); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// GetTags retrieves all tags of a user
+func (s *Storage) GetTags(userID int64) ([]Tag, error) {
+	query := `SELECT id, user_id, name, created_at FROM tags WHERE user_id = ? ORDER BY name`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []Tag
+	for rows.Next() {
+		var tag Tag
+		var createdAtStr string
+		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &createdAtStr); err != nil {
+			return nil, err
+		}
+		tag.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// AddTaskTag links a tag to a task
+func (s *Storage) AddTaskTag(taskID, tagID int64) error {
+	return retryOnBusy(func() error {
+		return addTaskTag(s.db, taskID, tagID)
+	}, 3)
+}
+
+// RemoveTaskTag unlinks a tag from a task
+func (s *Storage) RemoveTaskTag(taskID, tagID int64) error {
+	return retryOnBusy(func() error {
+		return removeTaskTag(s.db, taskID, tagID)
+	}, 3)
+}
+
+// addTaskTag is AddTaskTag on either the database or a transaction
+func addTaskTag(db execer, taskID, tagID int64) error {
+	query := `INSERT OR IGNORE INTO task_tags (task_id, tag_id) VALUES (?, ?)`
+	_, err := db.Exec(query, taskID, tagID)
+	return err
+}
+
+// removeTaskTag is RemoveTaskTag on either the database or a transaction
+func removeTaskTag(db execer, taskID, tagID int64) error {
+	query := `DELETE FROM task_tags WHERE task_id = ? AND tag_id = ?`
+	_, err := db.Exec(query, taskID, tagID)
+	return err
+}
+
+// AddSessionTag links a tag to a Pomodoro session
+func (s *Storage) AddSessionTag(sessionID, tagID int64) error {
+	return retryOnBusy(func() error {
+		query := `INSERT OR IGNORE INTO session_tags (session_id, tag_id) VALUES (?, ?)`
+		_, err := s.db.Exec(query, sessionID, tagID)
+		return err
+	}, 3)
+}
+
+// RemoveSessionTag unlinks a tag from a Pomodoro session
+func (s *Storage) RemoveSessionTag(sessionID, tagID int64) error {
+	return retryOnBusy(func() error {
+		query := `DELETE FROM session_tags WHERE session_id = ? AND tag_id = ?`
+		_, err := s.db.Exec(query, sessionID, tagID)
+		return err
+	}, 3)
+}
+
+// getTaskTags returns the tags of every task of a user, keyed by task ID
+func (s *Storage) getTaskTags(userID int64) (map[int64][]Tag, error) {
+	query := `SELECT tt.task_id, t.id, t.user_id, t.name, t.created_at
+	          FROM task_tags tt JOIN tags t ON t.id = tt.tag_id
+	          WHERE t.user_id = ? ORDER BY t.name`
+	return s.queryTagLinks(query, userID)
+}
+
+// getSessionTags returns the tags of every Pomodoro session of a user, keyed by session ID
+func (s *Storage) getSessionTags(userID int64) (map[int64][]Tag, error) {
+	query := `SELECT st.session_id, t.id, t.user_id, t.name, t.created_at
+	          FROM session_tags st JOIN tags t ON t.id = st.tag_id
+	          WHERE t.user_id = ? ORDER BY t.name`
+	return s.queryTagLinks(query, userID)
+}
+
+// queryTagLinks scans (owner ID, tag) rows into a map of tags by owner ID
+func (s *Storage) queryTagLinks(query string, args ...interface{}) (map[int64][]Tag, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make(map[int64][]Tag)
+	for rows.Next() {
+		var ownerID int64
+		var tag Tag
+		var createdAtStr string
+		if err := rows.Scan(&ownerID, &tag.ID, &tag.UserID, &tag.Name, &createdAtStr); err != nil {
+			return nil, err
+		}
+		tag.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %v", err)
+		}
+		tags[ownerID] = append(tags[ownerID], tag)
+	}
+	return tags, nil
+}
+
+// tagFilterClause builds a condition matching rows whose idColumn carries every one of the
+// given tags. linkTable and linkColumn name the join table and its owner column, e.g. task_tags.task_id.
+func tagFilterClause(idColumn, linkTable, linkColumn string, tags []string) (string, []interface{}) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(tags)), ", ")
+	clause := fmt.Sprintf(` AND %s IN (SELECT l.%s FROM %s l JOIN tags t ON t.id = l.tag_id
+	          WHERE t.name IN (%s) GROUP BY l.%s HAVING COUNT(DISTINCT t.name) = ?)`,
+		idColumn, linkColumn, linkTable, placeholders, linkColumn)
+
+	args := make([]interface{}, 0, len(tags)+1)
+	for _, tag := range tags {
+		args = append(args, tag)
+	}
+	args = append(args, len(tags))
+	return clause, args
+}
+
+// getTaskTagLinks returns every task-tag link of a user for export
+func (s *Storage) getTaskTagLinks(userID int64) ([]TaskTag, error) {
+	query := `SELECT tt.task_id, tt.tag_id FROM task_tags tt JOIN tags t ON t.id = tt.tag_id WHERE t.user_id = ?`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []TaskTag
+	for rows.Next() {
+		var link TaskTag
+		if err := rows.Scan(&link.TaskID, &link.TagID); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// getSessionTagLinks returns every session-tag link of a user for export
+func (s *Storage) getSessionTagLinks(userID int64) ([]SessionTag, error) {
+	query := `SELECT st.session_id, st.tag_id FROM session_tags st JOIN tags t ON t.id = st.tag_id WHERE t.user_id = ?`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []SessionTag
+	for rows.Next() {
+		var link SessionTag
+		if err := rows.Scan(&link.SessionID, &link.TagID); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
@@ -0,0 +1,109 @@
+package backend
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// templateColumns is the column list shared by all template queries, in scanTemplate order
+const templateColumns = `id, user_id, name, title_pattern, description, estimated_pomodoros, tags, checklist, created_at`
+
+// SaveTemplate creates a task template or replaces the one with the same ID
+func (s *Storage) SaveTemplate(template *TaskTemplate) error {
+	tags, err := json.Marshal(template.Tags)
+	if err != nil {
+		return err
+	}
+	checklist, err := json.Marshal(template.Checklist)
+	if err != nil {
+		return err
+	}
+
+	return retryOnBusy(func() error {
+		return saveTemplate(s.db, template, string(tags), string(checklist))
+	}, 3)
+}
+
+// saveTemplate writes a template with its tags and checklist already encoded
+func saveTemplate(db execer, template *TaskTemplate, tags, checklist string) error {
+	query := `INSERT INTO task_templates (id, user_id, name, title_pattern, description, estimated_pomodoros, tags, checklist, created_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	          ON CONFLICT(id) DO UPDATE SET
+	          name = excluded.name,
+	          title_pattern = excluded.title_pattern,
+	          description = excluded.description,
+	          estimated_pomodoros = excluded.estimated_pomodoros,
+	          tags = excluded.tags,
+	          checklist = excluded.checklist`
+	_, err := db.Exec(query, template.ID, template.UserID, template.Name, template.TitlePattern, template.Description,
+		template.EstimatedPomodoros, tags, checklist, template.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+// GetTemplates retrieves the task templates of a user
+func (s *Storage) GetTemplates(userID int64) ([]TaskTemplate, error) {
+	query := `SELECT ` + templateColumns + ` FROM task_templates WHERE user_id = ? ORDER BY name COLLATE NOCASE`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []TaskTemplate{}
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+	return templates, nil
+}
+
+// GetTemplate retrieves a single task template owned by a user
+func (s *Storage) GetTemplate(templateID, userID int64) (*TaskTemplate, error) {
+	query := `SELECT ` + templateColumns + ` FROM task_templates WHERE id = ? AND user_id = ?`
+	template, err := scanTemplate(s.db.QueryRow(query, templateID, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("template not found")
+	}
+	return template, err
+}
+
+// DeleteTemplate deletes a task template. Tasks created from it are kept.
+func (s *Storage) DeleteTemplate(templateID, userID int64) error {
+	return retryOnBusy(func() error {
+		query := `DELETE FROM task_templates WHERE id = ? AND user_id = ?`
+		_, err := s.db.Exec(query, templateID, userID)
+		return err
+	}, 3)
+}
+
+// scanTemplate scans a template row selected with templateColumns
+func scanTemplate(row interface{ Scan(...interface{}) error }) (*TaskTemplate, error) {
+	template := &TaskTemplate{}
+	var description sql.NullString
+	var tags, checklist, createdAtStr string
+
+	err := row.Scan(&template.ID, &template.UserID, &template.Name, &template.TitlePattern, &description,
+		&template.EstimatedPomodoros, &tags, &checklist, &createdAtStr)
+	if err != nil {
+		return nil, err
+	}
+	template.Description = description.String
+
+	if err := json.Unmarshal([]byte(tags), &template.Tags); err != nil {
+		return nil, fmt.Errorf("failed to parse template tags: %v", err)
+	}
+	if err := json.Unmarshal([]byte(checklist), &template.Checklist); err != nil {
+		return nil, fmt.Errorf("failed to parse template checklist: %v", err)
+	}
+
+	template.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %v", err)
+	}
+	return template, nil
+}
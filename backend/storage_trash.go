@@ -0,0 +1,148 @@
+package backend
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetDeletedTasks retrieves the tasks of a user that are in the trash, most recently deleted first
+func (s *Storage) GetDeletedTasks(userID int64) ([]Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks 
+	          WHERE user_id = ? AND deleted_at IS NOT NULL 
+	          ORDER BY deleted_at DESC, created_at DESC`
+	tasks, err := s.queryTasks(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, s.attachTaskTags(userID, tasks)
+}
+
+// RestoreTask takes a task out of the trash together with the subtasks that were deleted
+// along with it. A task whose parent is still in the trash is restored to the top level;
+// otherwise the parent has its completion rolled up in the same transaction.
+func (s *Storage) RestoreTask(taskID, userID int64) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var deletedAt string
+		query := `SELECT deleted_at FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL`
+		if err := tx.QueryRow(query, taskID, userID).Scan(&deletedAt); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("task not found in trash")
+			}
+			return err
+		}
+
+		restore := `WITH RECURSIVE subtree(id) AS (
+		              SELECT id FROM tasks WHERE id = ?
+		              UNION SELECT t.id FROM tasks t JOIN subtree ON t.parent_id = subtree.id
+		              WHERE t.deleted_at = ?
+		            )
+		            SELECT id FROM subtree`
+		taskIDs, err := queryIDs(tx, restore, taskID, deletedAt)
+		if err != nil {
+			return err
+		}
+		for _, id := range taskIDs {
+			if _, err := tx.Exec(`UPDATE tasks SET deleted_at = NULL WHERE id = ?`, id); err != nil {
+				return err
+			}
+			before := map[string]interface{}{"deleted_at": deletedAt}
+			if err := recordTaskEvent(tx, userID, id, TaskEventRestore, before, nil); err != nil {
+				return err
+			}
+		}
+
+		detach := `UPDATE tasks SET parent_id = NULL WHERE id = ? AND parent_id IN (
+		             SELECT id FROM tasks WHERE deleted_at IS NOT NULL
+		           )`
+		if _, err := tx.Exec(detach, taskID); err != nil {
+			return err
+		}
+
+		task, err := getTask(tx, taskID, userID)
+		if err != nil {
+			return err
+		}
+		if task.ParentID != nil {
+			if err := rollUpCompletion(tx, *task.ParentID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// PurgeTasks permanently removes the tasks of a user that were moved to the trash before
+// the given time. Sessions recorded on them are kept, and each task with sessions leaves a
+// PurgedTask behind so that reports still show its title. Their audit log is kept as well.
+// Their attachments are removed, leaving the stored files to the orphaned file cleanup.
+func (s *Storage) PurgeTasks(userID int64, before time.Time) (int, error) {
+	var purged int
+	err := retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		expired := `SELECT id FROM tasks WHERE user_id = ? AND deleted_at IS NOT NULL AND deleted_at < ?`
+		args := []interface{}{userID, before.Format(time.RFC3339)}
+
+		tombstones := `INSERT OR REPLACE INTO purged_tasks (id, user_id, title, purged_at)
+		               SELECT id, user_id, title, ? FROM tasks
+		               WHERE id IN (` + expired + `) AND id IN (SELECT task_id FROM pomodoro_sessions)`
+		if _, err := tx.Exec(tombstones, append([]interface{}{time.Now().Format(time.RFC3339)}, args...)...); err != nil {
+			return err
+		}
+
+		statements := []string{
+			`UPDATE tasks SET parent_id = NULL WHERE parent_id IN (` + expired + `)`,
+			`DELETE FROM task_tags WHERE task_id IN (` + expired + `)`,
+			`DELETE FROM task_transitions WHERE task_id IN (` + expired + `)`,
+			`DELETE FROM task_dependencies WHERE task_id IN (` + expired + `)`,
+			`DELETE FROM task_dependencies WHERE blocked_by_id IN (` + expired + `)`,
+			`DELETE FROM tasks_fts WHERE rowid IN (` + expired + `)`,
+			`DELETE FROM task_attachments WHERE task_id IN (` + expired + `)`,
+		}
+		for _, statement := range statements {
+			if _, err := tx.Exec(statement, args...); err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.Exec(`DELETE FROM tasks WHERE id IN (`+expired+`)`, args...)
+		if err != nil {
+			return err
+		}
+		n, _ := result.RowsAffected()
+		purged = int(n)
+		return tx.Commit()
+	}, 3)
+	return purged, err
+}
+
+// getPurgedTasks retrieves what is kept of the tasks of a user that were purged from the trash
+func (s *Storage) getPurgedTasks(userID int64) ([]PurgedTask, error) {
+	query := `SELECT id, user_id, title, purged_at FROM purged_tasks WHERE user_id = ?`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []PurgedTask{}
+	for rows.Next() {
+		var task PurgedTask
+		if err := rows.Scan(&task.ID, &task.UserID, &task.Title, &task.PurgedAt); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
@@ -0,0 +1,260 @@
+package backend
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// fillTaskStates puts tasks without a state in the default workflow, by their completion
+func fillTaskStates(db execer) error {
+	open := defaultWorkflowStateNames[0]
+	terminal := defaultWorkflowStateNames[len(defaultWorkflowStateNames)-1]
+	query := `UPDATE tasks SET state = CASE WHEN completed = 1 THEN ? ELSE ? END WHERE state IS NULL`
+	_, err := db.Exec(query, terminal, open)
+	return err
+}
+
+// getWorkflowStates returns the configured workflow states of a user in order, or the
+// default ones when the user has not configured any
+func getWorkflowStates(db queryer, userID int64) ([]WorkflowState, error) {
+	query := `SELECT id, user_id, name, position, terminal FROM workflow_states WHERE user_id = ? ORDER BY position`
+	rows, err := db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var states []WorkflowState
+	for rows.Next() {
+		var state WorkflowState
+		if err := rows.Scan(&state.ID, &state.UserID, &state.Name, &state.Position, &state.Terminal); err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(states) == 0 {
+		return defaultWorkflowStates(userID), nil
+	}
+	return states, nil
+}
+
+// GetWorkflowStates retrieves the workflow states of a user in order. The default workflow
+// is stored on first use, so that its states have IDs and can be renamed.
+func (s *Storage) GetWorkflowStates(userID int64) ([]WorkflowState, error) {
+	states, err := getWorkflowStates(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if states[0].ID != 0 {
+		return states, nil
+	}
+
+	if err := s.SaveWorkflowStates(userID, states); err != nil {
+		return nil, err
+	}
+	return getWorkflowStates(s.db, userID)
+}
+
+// SaveWorkflowStates replaces the workflow of a user with the given states, in order.
+// States are matched to the existing ones by ID, so tasks follow a renamed state. Tasks in
+// a state that no longer exists move to the terminal state when completed, or to the first
+// open state otherwise, and every task's completion is brought in line with its state.
+func (s *Storage) SaveWorkflowStates(userID int64, states []WorkflowState) error {
+	return retryOnBusy(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		previous, err := getWorkflowStates(tx, userID)
+		if err != nil {
+			return err
+		}
+		previousByID := make(map[int64]string)
+		for _, state := range previous {
+			if state.ID != 0 {
+				previousByID[state.ID] = state.Name
+			}
+		}
+
+		// Keep the IDs of existing states so renames can be followed
+		renamed := make(map[string]string)
+		saved := make([]WorkflowState, len(states))
+		for i, state := range states {
+			state.UserID = userID
+			state.Position = i
+			if name, ok := previousByID[state.ID]; ok {
+				renamed[name] = state.Name
+			} else {
+				state.ID = GenerateID()
+			}
+			saved[i] = state
+		}
+
+		if _, err := tx.Exec(`DELETE FROM workflow_states WHERE user_id = ?`, userID); err != nil {
+			return err
+		}
+		stmt, err := tx.Prepare(`INSERT INTO workflow_states (id, user_id, name, position, terminal) VALUES (?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for _, state := range saved {
+			if _, err := stmt.Exec(state.ID, state.UserID, state.Name, state.Position, state.Terminal); err != nil {
+				return err
+			}
+		}
+
+		if err := moveTasksToWorkflow(tx, userID, saved, renamed); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}, 3)
+}
+
+// moveTasksToWorkflow updates the state and completion of every task of a user after the
+// workflow changed, recording a transition for each task whose state changed. A change in
+// completion is rolled up and spawns the next occurrence like any other.
+func moveTasksToWorkflow(tx *sql.Tx, userID int64, states []WorkflowState, renamed map[string]string) error {
+	taskIDs, err := queryIDs(tx, `SELECT id FROM tasks WHERE user_id = ? AND deleted_at IS NULL`, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, taskID := range taskIDs {
+		// Read each task only now, as rolling up an earlier one may have changed it
+		task, err := getTask(tx, taskID, userID)
+		if err != nil {
+			return err
+		}
+
+		state := task.State
+		if name, ok := renamed[state]; ok {
+			state = name
+		}
+		target := findWorkflowState(states, state)
+		if target == nil {
+			target = findWorkflowState(states, workflowStateFor(states, task.Completed))
+		}
+
+		before := make(map[string]interface{})
+		after := make(map[string]interface{})
+
+		if target.Name != task.State {
+			before["state"], after["state"] = task.State, target.Name
+			query := `UPDATE tasks SET state = ? WHERE id = ?`
+			if _, err := tx.Exec(query, target.Name, task.ID); err != nil {
+				return err
+			}
+			// A rename is not a move, so only record tasks that changed state
+			if state != target.Name || task.State == "" {
+				transition := &TaskTransition{
+					ID:        GenerateID(),
+					UserID:    userID,
+					TaskID:    task.ID,
+					FromState: task.State,
+					ToState:   target.Name,
+					MovedAt:   now,
+				}
+				if err := insertTaskTransition(tx, transition); err != nil {
+					return err
+				}
+			}
+		}
+
+		action := TaskEventUpdate
+		if target.Terminal != task.Completed {
+			before["completed"], after["completed"] = task.Completed, target.Terminal
+			action = TaskEventReopen
+			var completedAt interface{}
+			if target.Terminal {
+				action = TaskEventComplete
+				completedAt = now.Format(time.RFC3339)
+			}
+			query := `UPDATE tasks SET completed = ?, completed_at = ? WHERE id = ?`
+			if _, err := tx.Exec(query, target.Terminal, completedAt, task.ID); err != nil {
+				return err
+			}
+		}
+
+		if len(after) > 0 {
+			if err := recordTaskEvent(tx, userID, task.ID, action, before, after); err != nil {
+				return err
+			}
+		}
+		if err := applyCompletionChange(tx, task, target.Terminal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertTaskTransition records a task moving between workflow states
+func insertTaskTransition(db execer, transition *TaskTransition) error {
+	query := `INSERT INTO task_transitions (id, user_id, task_id, from_state, to_state, moved_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := db.Exec(query, transition.ID, transition.UserID, transition.TaskID,
+		nullableString(transition.FromState), transition.ToState, transition.MovedAt.Format(time.RFC3339))
+	return err
+}
+
+// GetTaskTransitions retrieves the state transitions of a task, oldest first
+func (s *Storage) GetTaskTransitions(taskID, userID int64) ([]TaskTransition, error) {
+	query := `SELECT id, user_id, task_id, from_state, to_state, moved_at FROM task_transitions
+	          WHERE task_id = ? AND user_id = ? ORDER BY moved_at, id`
+	return s.queryTaskTransitions(query, taskID, userID)
+}
+
+// getAllTransitionsForUser returns every state transition of a user for export
+func (s *Storage) getAllTransitionsForUser(userID int64) ([]TaskTransition, error) {
+	query := `SELECT id, user_id, task_id, from_state, to_state, moved_at FROM task_transitions WHERE user_id = ?`
+	return s.queryTaskTransitions(query, userID)
+}
+
+// queryTaskTransitions runs a transition query and scans every row
+func (s *Storage) queryTaskTransitions(query string, args ...interface{}) ([]TaskTransition, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := []TaskTransition{}
+	for rows.Next() {
+		var transition TaskTransition
+		var fromState sql.NullString
+		var movedAtStr string
+		if err := rows.Scan(&transition.ID, &transition.UserID, &transition.TaskID, &fromState,
+			&transition.ToState, &movedAtStr); err != nil {
+			return nil, err
+		}
+		transition.FromState = fromState.String
+		transition.MovedAt, err = time.Parse(time.RFC3339, movedAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse moved_at: %v", err)
+		}
+		transitions = append(transitions, transition)
+	}
+	return transitions, nil
+}
+
+// getStoredWorkflowStates returns the workflow states a user has stored, for export
+func (s *Storage) getStoredWorkflowStates(userID int64) ([]WorkflowState, error) {
+	states, err := getWorkflowStates(s.db, userID)
+	if err != nil || states[0].ID == 0 {
+		return nil, err
+	}
+	return states, nil
+}
@@ -0,0 +1,54 @@
+package backend
+
+import (
+	"strings"
+	"time"
+)
+
+// Tag represents a label such as "deep-work" that can be put on tasks and sessions
+type Tag struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaskTag links a tag to a task
+type TaskTag struct {
+	TaskID int64 `json:"task_id"`
+	TagID  int64 `json:"tag_id"`
+}
+
+// SessionTag links a tag to a Pomodoro session
+type SessionTag struct {
+	SessionID int64 `json:"session_id"`
+	TagID     int64 `json:"tag_id"`
+}
+
+// TagReport represents the time spent under a tag within a report range
+type TagReport struct {
+	TagID    int64  `json:"tag_id"`
+	Name     string `json:"name"`
+	Sessions int    `json:"sessions"`
+	Minutes  int    `json:"minutes"`
+}
+
+// normalizeTagName trims and lowercases a tag name so "Deep-Work " and "deep-work" match
+func normalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// normalizeTagNames normalizes a list of tag names, dropping empty ones and duplicates
+func normalizeTagNames(names []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, name := range names {
+		name = normalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
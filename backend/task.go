@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -10,9 +11,141 @@ type Task struct {
 	UserID      int64      `json:"user_id"`
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
-	Completed   bool       `json:"completed"`
+	Completed   bool       `json:"completed"` // Kept in step with State, see WorkflowState.Terminal
 	CreatedAt   time.Time  `json:"created_at"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ProjectID   *int64     `json:"project_id,omitempty"`
+	Tags        []Tag      `json:"tags"`
+
+	// Subtasks
+	ParentID     *int64        `json:"parent_id,omitempty"`
+	AutoComplete bool          `json:"auto_complete"` // Complete this task once all subtasks are completed
+	Children     []Task        `json:"children,omitempty"`
+	Progress     *TaskProgress `json:"progress,omitempty"`
+
+	EstimatedPomodoros int `json:"estimated_pomodoros"` // 0 means no estimate
+
+	DueDate  string `json:"due_date,omitempty"` // Format: YYYY-MM-DD
+	Priority int    `json:"priority"`           // One of the TaskPriority constants
+	SortRank int    `json:"sort_rank"`          // Manual drag-and-drop position, lower comes first
+
+	Recurrence string `json:"recurrence,omitempty"` // RRULE, see RecurrenceRule
+	Occurrence int    `json:"occurrence"`           // 1-based position of this task in its series
+
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // Set while the task is in the trash
+
+	State string `json:"state"` // Name of the task's WorkflowState
+
+	// Dependencies
+	BlockedBy []int64 `json:"blocked_by,omitempty"` // IDs of the tasks this task waits for
+	Blocked   bool    `json:"blocked"`              // Some task in BlockedBy is not completed yet; the task is ready otherwise
+}
+
+// PurgedTask is what is kept of a task removed from the trash for good, so that the
+// sessions recorded on it still show its title
+type PurgedTask struct {
+	ID       int64     `json:"id"`
+	UserID   int64     `json:"user_id"`
+	Title    string    `json:"title"`
+	PurgedAt time.Time `json:"purged_at"`
+}
+
+// Task priorities, from no priority to the most urgent
+const (
+	TaskPriorityNone = iota
+	TaskPriorityLow
+	TaskPriorityMedium
+	TaskPriorityHigh
+)
+
+// Task query modes accepted in TaskFilter.Mode
+const (
+	TaskModeAll      = ""         // Manual order, newest first among unranked tasks
+	TaskModeToday    = "today"    // Due today
+	TaskModeOverdue  = "overdue"  // Open tasks due before today
+	TaskModeUpcoming = "upcoming" // Due after today, soonest first
+	TaskModePriority = "priority" // Highest priority first, then soonest due
+	TaskModeReady    = "ready"    // Open tasks that are not blocked, most urgent first
+)
+
+// TaskProgress summarizes how many direct subtasks of a task are completed
+type TaskProgress struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+}
+
+// TaskEstimate compares the estimated and actual pomodoros of a task
+type TaskEstimate struct {
+	TaskID    int64  `json:"task_id"`
+	Title     string `json:"title"`
+	Estimated int    `json:"estimated"`
+	Actual    int    `json:"actual"`
+	Overrun   int    `json:"overrun"` // Actual minus estimated; negative for an under-run
+}
+
+// EstimationReport summarizes how well pomodoro estimates matched reality
+type EstimationReport struct {
+	Tasks          []TaskEstimate `json:"tasks"`
+	TotalEstimated int            `json:"total_estimated"`
+	TotalActual    int            `json:"total_actual"`
+	OverRuns       int            `json:"over_runs"`
+	UnderRuns      int            `json:"under_runs"`
+	OnTarget       int            `json:"on_target"`
+	Accuracy       float64        `json:"accuracy"` // 1.0 when every estimate was exact
+}
+
+// TaskFilter narrows down the tasks returned by GetTasks
+type TaskFilter struct {
+	Tags []string `json:"tags"` // Only tasks carrying all of these tags
+	Mode string   `json:"mode"` // One of the TaskMode constants
+}
+
+// isEmpty reports whether the filter matches every task in the default order
+func (f TaskFilter) isEmpty() bool {
+	return len(f.Tags) == 0 && f.Mode == TaskModeAll
+}
+
+// buildTaskTree nests tasks under their parents, keeping the order of the input.
+// Tasks whose parent is not part of the list are returned at the top level.
+func buildTaskTree(tasks []Task) []Task {
+	children := make(map[int64][]int)
+	present := make(map[int64]bool, len(tasks))
+	for _, task := range tasks {
+		present[task.ID] = true
+	}
+	var roots []int
+	for i, task := range tasks {
+		if task.ParentID != nil && present[*task.ParentID] {
+			children[*task.ParentID] = append(children[*task.ParentID], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var build func(i int) Task
+	build = func(i int) Task {
+		task := tasks[i]
+		task.Children = nil
+		task.Progress = nil
+		for _, c := range children[task.ID] {
+			task.Children = append(task.Children, build(c))
+		}
+		if len(task.Children) > 0 {
+			task.Progress = &TaskProgress{Total: len(task.Children)}
+			for _, child := range task.Children {
+				if child.Completed {
+					task.Progress.Completed++
+				}
+			}
+		}
+		return task
+	}
+
+	result := make([]Task, 0, len(roots))
+	for _, i := range roots {
+		result = append(result, build(i))
+	}
+	return result
 }
 
 // PomodoroSession represents a completed Pomodoro session
@@ -23,6 +156,15 @@ type PomodoroSession struct {
 	Duration    int       `json:"duration"` // Duration in minutes
 	StartedAt   time.Time `json:"started_at"`
 	CompletedAt time.Time `json:"completed_at"`
+	Tags        []Tag     `json:"tags"`
+	Notes       string    `json:"notes,omitempty"`
+	Mode        string    `json:"mode"`             // One of the TimerMode constants
+	Outcome     string    `json:"outcome"`          // One of the SessionOutcome constants
+	Reason      string    `json:"reason,omitempty"` // Why an abandoned session was stopped
+
+	PausedSeconds int            `json:"paused_seconds"` // Time the timer spent paused between StartedAt and CompletedAt
+	Pauses        []PauseSegment `json:"pauses"`
+	Interruptions []Interruption `json:"interruptions"`
 }
 
 // TimerState represents the current state of the Pomodoro timer
@@ -33,6 +175,113 @@ type TimerState struct {
 	TimeRemaining int       `json:"time_remaining"` // Remaining time in seconds
 	TaskID        *int64    `json:"task_id,omitempty"`
 	StartedAt     time.Time `json:"started_at,omitempty"`
+	Mode          string    `json:"mode"`    // One of the TimerMode constants
+	Elapsed       int       `json:"elapsed"` // Time the running phase has run in seconds, not counting pauses
+
+	PausedAt      *time.Time     `json:"paused_at,omitempty"`     // Set while paused
+	PausedSeconds int            `json:"paused_seconds"`          // Time spent paused in earlier pauses of this phase
+	Pauses        []PauseSegment `json:"pauses,omitempty"`        // Earlier pauses of this phase
+	Interruptions []Interruption `json:"interruptions,omitempty"` // Interruptions logged during this phase
+	TaskSwitches  []TaskSwitch   `json:"task_switches,omitempty"` // Changes of task during this phase, earliest first
+
+	// Set while paused because the user went idle; PausedAt is when they went idle
+	IdlePaused     bool       `json:"idle_paused"`
+	IdleReturnedAt *time.Time `json:"idle_returned_at,omitempty"` // When the user came back, until they settle the idle time
+
+	// Cycle
+	Phase          string `json:"phase"`            // One of the PomodoroPhase constants; the next phase while not running
+	CycleCount     int    `json:"cycle_count"`      // Work phases completed since the last long break
+	LongBreakEvery int    `json:"long_break_every"` // Work phases before a long break
+}
+
+// TaskSwitch records the running timer moving off a task. The time up to SwitchedAt is
+// attributed to TaskID.
+type TaskSwitch struct {
+	TaskID     *int64    `json:"task_id,omitempty"`
+	SwitchedAt time.Time `json:"switched_at"`
+}
+
+// Modes of the timer
+const (
+	TimerModePomodoro  = "pomodoro"  // Counts a phase of the cycle down
+	TimerModeStopwatch = "stopwatch" // Counts up until stopped, for free-form time tracking
+)
+
+// Outcomes of a recorded session
+const (
+	SessionOutcomeCompleted = "completed" // Ran its full length, or was tracked by hand or with the stopwatch
+	SessionOutcomeAbandoned = "abandoned" // A work phase stopped before its end
+)
+
+// Phases of the Pomodoro cycle
+const (
+	PomodoroPhaseWork       = "work"
+	PomodoroPhaseShortBreak = "short_break"
+	PomodoroPhaseLongBreak  = "long_break"
+)
+
+// PomodoroPhaseChange is the payload of the "timer:phase" event
+type PomodoroPhaseChange struct {
+	From  string     `json:"from"`
+	To    string     `json:"to"`
+	State TimerState `json:"state"`
+}
+
+// PomodoroSettings configures the Pomodoro cycle of a user
+type PomodoroSettings struct {
+	WorkMins        int  `json:"work_mins"`
+	ShortBreakMins  int  `json:"short_break_mins"`
+	LongBreakMins   int  `json:"long_break_mins"`
+	LongBreakEvery  int  `json:"long_break_every"`  // Take a long break after this many work phases
+	AutoStartBreaks bool `json:"auto_start_breaks"` // Start a break as soon as a work phase ends
+	AutoStartWork   bool `json:"auto_start_work"`   // Start a work phase as soon as a break ends
+	MinRecordMins   int  `json:"min_record_mins"`   // Stopped sessions with less focus time are not recorded
+	IdlePauseMins   int  `json:"idle_pause_mins"`   // Pause focus time once the user is idle this long; 0 turns it off
+}
+
+// defaultPomodoroSettings is the classic cycle: 25 minutes of work, 5 minute breaks and a
+// 15 minute break after every 4 pomodoros
+var defaultPomodoroSettings = PomodoroSettings{
+	WorkMins:       25,
+	ShortBreakMins: 5,
+	LongBreakMins:  15,
+	LongBreakEvery: 4,
+	MinRecordMins:  1,
+	IdlePauseMins:  5,
+}
+
+// phaseMinutes returns the length of a phase in minutes
+func (s PomodoroSettings) phaseMinutes(phase string) int {
+	switch phase {
+	case PomodoroPhaseShortBreak:
+		return s.ShortBreakMins
+	case PomodoroPhaseLongBreak:
+		return s.LongBreakMins
+	default:
+		return s.WorkMins
+	}
+}
+
+// maxPhaseMins is the longest a phase of the Pomodoro cycle can last
+const maxPhaseMins = 240
+
+// validate checks that every phase has a sensible length
+func (s PomodoroSettings) validate() error {
+	for _, mins := range []int{s.WorkMins, s.ShortBreakMins, s.LongBreakMins} {
+		if mins < 1 || mins > maxPhaseMins {
+			return fmt.Errorf("phase length must be between 1 and %d minutes", maxPhaseMins)
+		}
+	}
+	if s.LongBreakEvery < 1 {
+		return fmt.Errorf("long break interval must be at least 1")
+	}
+	if s.MinRecordMins < 1 || s.MinRecordMins > 60 {
+		return fmt.Errorf("minimum recorded session must be between 1 and 60 minutes")
+	}
+	if s.IdlePauseMins < 0 || s.IdlePauseMins > 120 {
+		return fmt.Errorf("idle pause must be between 0 and 120 minutes")
+	}
+	return nil
 }
 
 // WaterReminderSettings represents water reminder configuration
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TaskTemplate describes a task that is created over and over, such as a weekly report.
+// Title, description and checklist items may contain placeholders, see expandTemplate.
+type TaskTemplate struct {
+	ID                 int64     `json:"id"`
+	UserID             int64     `json:"user_id"`
+	Name               string    `json:"name"`
+	TitlePattern       string    `json:"title_pattern"`
+	Description        string    `json:"description"`
+	EstimatedPomodoros int       `json:"estimated_pomodoros"`
+	Tags               []string  `json:"tags"`
+	Checklist          []string  `json:"checklist"` // Each item becomes a subtask
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// expandTemplate replaces the placeholders in a template text with values for a date:
+// {date} (2026-10-16), {week} (2026-W42), {month} (2026-10) and {year} (2026)
+func expandTemplate(text string, date time.Time) string {
+	year, week := date.ISOWeek()
+	return strings.NewReplacer(
+		"{date}", date.Format("2006-01-02"),
+		"{week}", fmt.Sprintf("%d-W%02d", year, week),
+		"{month}", date.Format("2006-01"),
+		"{year}", date.Format("2006"),
+	).Replace(text)
+}
+
+// normalizeTemplate trims the template fields and drops empty checklist items and tags
+func normalizeTemplate(template *TaskTemplate) error {
+	template.TitlePattern = strings.TrimSpace(template.TitlePattern)
+	if template.TitlePattern == "" {
+		return fmt.Errorf("template title is required")
+	}
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		template.Name = template.TitlePattern
+	}
+	if template.EstimatedPomodoros < 0 {
+		return fmt.Errorf("estimate cannot be negative")
+	}
+
+	checklist := []string{}
+	for _, item := range template.Checklist {
+		if item = strings.TrimSpace(item); item != "" {
+			checklist = append(checklist, item)
+		}
+	}
+	template.Checklist = checklist
+
+	template.Tags = normalizeTagNames(template.Tags)
+	if template.Tags == nil {
+		template.Tags = []string{}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorkflowState is a stage tasks move through on the board, such as "in_progress"
+type WorkflowState struct {
+	ID       int64  `json:"id"`
+	UserID   int64  `json:"user_id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+	Terminal bool   `json:"terminal"` // Tasks in the terminal state are the completed ones
+}
+
+// TaskTransition records a task moving from one workflow state to another
+type TaskTransition struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	TaskID    int64     `json:"task_id"`
+	FromState string    `json:"from_state"`
+	ToState   string    `json:"to_state"`
+	MovedAt   time.Time `json:"moved_at"`
+}
+
+// BoardColumn holds the tasks in one workflow state
+type BoardColumn struct {
+	State WorkflowState `json:"state"`
+	Tasks []Task        `json:"tasks"`
+}
+
+// defaultWorkflowStateNames are the states of a user who has not configured any, in order.
+// The last one is terminal.
+var defaultWorkflowStateNames = []string{"todo", "in_progress", "review", "done"}
+
+// defaultWorkflowStates returns the default workflow of a user
+func defaultWorkflowStates(userID int64) []WorkflowState {
+	states := make([]WorkflowState, len(defaultWorkflowStateNames))
+	for i, name := range defaultWorkflowStateNames {
+		states[i] = WorkflowState{
+			UserID:   userID,
+			Name:     name,
+			Position: i,
+			Terminal: i == len(defaultWorkflowStateNames)-1,
+		}
+	}
+	return states
+}
+
+// findWorkflowState returns the state with the given name, or nil
+func findWorkflowState(states []WorkflowState, name string) *WorkflowState {
+	for i := range states {
+		if states[i].Name == name {
+			return &states[i]
+		}
+	}
+	return nil
+}
+
+// workflowStateFor returns the state a task with the given completion lands in when it has no
+// valid state of its own: the terminal state when completed, otherwise the first open state
+func workflowStateFor(states []WorkflowState, completed bool) string {
+	for _, state := range states {
+		if state.Terminal == completed {
+			return state.Name
+		}
+	}
+	return ""
+}
+
+// validateWorkflowStates trims the state names and checks that they are unique and that
+// exactly one state is terminal, with at least one open state besides it
+func validateWorkflowStates(states []WorkflowState) error {
+	seen := make(map[string]bool)
+	terminal := 0
+	for i := range states {
+		states[i].Name = strings.TrimSpace(states[i].Name)
+		if states[i].Name == "" {
+			return fmt.Errorf("workflow state name cannot be empty")
+		}
+		if seen[states[i].Name] {
+			return fmt.Errorf("duplicate workflow state: %s", states[i].Name)
+		}
+		seen[states[i].Name] = true
+		if states[i].Terminal {
+			terminal++
+		}
+	}
+
+	if terminal != 1 {
+		return fmt.Errorf("workflow must have exactly one terminal state")
+	}
+	if len(states) < 2 {
+		return fmt.Errorf("workflow must have at least one state before the terminal one")
+	}
+	return nil
+}
@@ -3,14 +3,19 @@ import { useTranslation } from 'react-i18next';
 import { Card, CardContent, CardHeader, CardTitle } from './ui/card';
 import { Button } from './ui/button';
 import { Label } from './ui/label';
+import { Popup } from './Popup';
 import { useToast } from '../hooks/use-toast';
 import {
   StartPomodoro,
+  StartStopwatch,
   PausePomodoro,
   ResumePomodoro,
   StopPomodoro,
+  ExtendPomodoro,
+  SkipPhase,
+  SwitchTask,
+  ResolveIdle,
   GetTimerState,
-  CompletePomodoro,
   GetTasks,
   LockScreen
 } from '../../wailsjs/go/backend/App';
@@ -21,12 +26,18 @@ export function PomodoroTimer() {
   const { toast } = useToast();
   const [duration, setDuration] = useState(25);
   const [selectedTask, setSelectedTask] = useState<number | null>(null);
+  const [idleTask, setIdleTask] = useState<number | null>(null);
   const [tasks, setTasks] = useState<any[]>([]);
   const [timerState, setTimerState] = useState<any>({
     is_running: false,
     is_paused: false,
     time_remaining: 0,
-    duration: 0
+    duration: 0,
+    mode: 'pomodoro',
+    elapsed: 0,
+    phase: 'work',
+    cycle_count: 0,
+    long_break_every: 4
   });
   const [audio] = useState(() => {
     const audio = new Audio();
@@ -40,25 +51,52 @@ export function PomodoroTimer() {
     loadTasks();
     updateTimerState();
 
-    const interval = setInterval(() => {
-      if (timerState.is_running) {
-        updateTimerState();
-      }
-    }, 1000);
+    // The backend sends the timer state every second while a phase runs
+    const offTick = EventsOn('timer:tick', (state) => {
+      setTimerState(state);
+    });
 
     // Listen for timer complete event
-    EventsOn('timer:complete', () => {
+    const offComplete = EventsOn('timer:complete', () => {
       handleTimerComplete();
       LockScreen();
     });
 
-    return () => clearInterval(interval);
+    // The backend pauses the timer when the user walks away
+    const offIdle = EventsOn('timer:idle', (state) => {
+      setTimerState(state);
+      toast({
+        title: t('pause'),
+        description: t('idle_paused'),
+      });
+    });
+
+    // Once the user is back, timerState.idle_returned_at opens the idle prompt
+    const offIdleReturn = EventsOn('timer:idle-return', (state) => {
+      setTimerState(state);
+    });
+
+    // Listen for the cycle moving on to a break or back to work
+    const offPhase = EventsOn('timer:phase', () => {
+      updateTimerState();
+    });
+
+    return () => {
+      offTick();
+      offComplete();
+      offIdle();
+      offIdleReturn();
+      offPhase();
+    };
   }, [timerState.is_running]);
 
   const loadTasks = async () => {
     try {
-      const taskList = await GetTasks();
-      setTasks(taskList || []);
+      const taskList = await GetTasks({ tags: [], mode: '' });
+      // Subtasks can be timed too, so flatten the task tree for the selector
+      const flatten = (list: any[]): any[] =>
+        list.flatMap((task) => [task, ...flatten(task.children || [])]);
+      setTasks(flatten(taskList || []));
     } catch (err) {
       console.error('Failed to load tasks:', err);
     }
@@ -73,10 +111,9 @@ export function PomodoroTimer() {
     }
   };
 
+  // The backend records the finished session itself
   const handleTimerComplete = async () => {
     try {
-      await CompletePomodoro(duration, selectedTask || null);
-
       // Play notification sound
       try {
         await audio.play();
@@ -109,15 +146,24 @@ export function PomodoroTimer() {
     }
   };
 
-  const handleStart = async () => {
+  const handleStart = async (stopwatch = false) => {
     try {
-      await StartPomodoro(duration, selectedTask || null);
+      const blockers = stopwatch
+        ? await StartStopwatch(selectedTask || null)
+        : await StartPomodoro(duration, selectedTask || null);
       updateTimerState();
-      toast({
-        title: t('success'),
-        description: t('pomodoro_started'),
-        variant: 'success',
-      });
+      if (blockers && blockers.length > 0) {
+        toast({
+          title: t('task_blocked'),
+          description: t('task_blocked_by') + blockers.map((task) => task.title).join(', '),
+        });
+      } else {
+        toast({
+          title: t('success'),
+          description: t(stopwatch ? 'stopwatch_started' : 'pomodoro_started'),
+          variant: 'success',
+        });
+      }
     } catch (err) {
       console.error('Failed to start timer:', err);
       toast({
@@ -154,7 +200,8 @@ export function PomodoroTimer() {
 
   const handleStop = async () => {
     try {
-      await StopPomodoro();
+      // A work phase stopped early is recorded as abandoned
+      await StopPomodoro('');
       updateTimerState();
       toast({
         title: t('success'),
@@ -170,12 +217,88 @@ export function PomodoroTimer() {
     }
   };
 
+  const handleExtend = async () => {
+    try {
+      await ExtendPomodoro(5);
+      updateTimerState();
+    } catch (err) {
+      console.error('Failed to extend timer:', err);
+      toast({
+        title: t('error'),
+        description: t('error'),
+        variant: 'destructive',
+      });
+    }
+  };
+
+  const handleSkip = async () => {
+    try {
+      await SkipPhase();
+      updateTimerState();
+    } catch (err) {
+      console.error('Failed to skip phase:', err);
+      toast({
+        title: t('error'),
+        description: t('error'),
+        variant: 'destructive',
+      });
+    }
+  };
+
+  // The time before the switch stays with the previous task
+  const handleSwitchTask = async (taskID: number | null) => {
+    try {
+      const blockers = await SwitchTask(taskID);
+      setSelectedTask(taskID);
+      updateTimerState();
+      if (blockers && blockers.length > 0) {
+        toast({
+          title: t('task_blocked'),
+          description: t('task_blocked_by') + blockers.map((task) => task.title).join(', '),
+        });
+      } else {
+        toast({
+          title: t('success'),
+          description: t('task_switched'),
+        });
+      }
+    } catch (err) {
+      console.error('Failed to switch task:', err);
+      toast({
+        title: t('error'),
+        description: t('error'),
+        variant: 'destructive',
+      });
+    }
+  };
+
+  const handleResolveIdle = async (action: string) => {
+    try {
+      await ResolveIdle(action, action === 'reassign' ? idleTask : null);
+      updateTimerState();
+    } catch (err) {
+      console.error('Failed to settle idle time:', err);
+      toast({
+        title: t('error'),
+        description: t('error'),
+        variant: 'destructive',
+      });
+    }
+  };
+
   const formatTime = (seconds: number) => {
     const mins = Math.floor(seconds / 60);
     const secs = seconds % 60;
     return `${mins.toString().padStart(2, '0')}:${secs.toString().padStart(2, '0')}`;
   };
 
+  const isStopwatch = timerState.is_running && timerState.mode === 'stopwatch';
+  const isFocusing = timerState.is_running && (isStopwatch || timerState.phase === 'work');
+
+  const idleMinutes = timerState.idle_returned_at && timerState.paused_at
+    ? Math.round((new Date(timerState.idle_returned_at).getTime() - new Date(timerState.paused_at).getTime()) / 60000)
+    : 0;
+
   const progressPercent = timerState.duration > 0
     ? ((timerState.duration - timerState.time_remaining) / timerState.duration) * 100
     : 0;
@@ -215,11 +338,20 @@ export function PomodoroTimer() {
               </svg>
               <div className="absolute inset-0 flex items-center justify-center">
                 <div className="text-center">
+                  <div className="text-sm font-medium">
+                    {isStopwatch
+                      ? t('stopwatch')
+                      : `${t('phase_' + (timerState.phase || 'work'))} · ${timerState.cycle_count}/${timerState.long_break_every}`}
+                  </div>
                   <div className="text-5xl font-bold">
-                    {timerState.is_running ? formatTime(timerState.time_remaining) : formatTime(duration * 60)}
+                    {isStopwatch
+                      ? formatTime(timerState.elapsed || 0)
+                      : timerState.is_running
+                      ? formatTime(timerState.time_remaining)
+                      : formatTime(timerState.phase === 'work' ? duration * 60 : timerState.duration)}
                   </div>
                   <div className="text-sm text-muted-foreground mt-2">
-                    {timerState.is_paused ? t('pause') : t('time_remaining')}
+                    {timerState.is_paused ? t('pause') : isStopwatch ? t('time_elapsed') : t('time_remaining')}
                   </div>
                 </div>
               </div>
@@ -227,7 +359,7 @@ export function PomodoroTimer() {
           </div>
 
           {/* Controls when timer is not running */}
-          {!timerState.is_running && (
+          {!timerState.is_running && timerState.phase === 'work' && (
             <>
               <div className="space-y-2">
                 <Label>{t('pomodoro_duration')}</Label>
@@ -263,13 +395,43 @@ export function PomodoroTimer() {
             </>
           )}
 
+          {/* Switching task keeps the timer running */}
+          {isFocusing && (
+            <div className="space-y-2">
+              <Label>{t('switch_task')}</Label>
+              <select
+                value={timerState.task_id || ''}
+                onChange={(e) => handleSwitchTask(e.target.value ? parseInt(e.target.value) : null)}
+                className="w-full h-10 rounded-md border border-input bg-background px-3 py-2 text-sm ring-offset-background focus-visible:outline-none focus-visible:ring-2 focus-visible:ring-ring"
+              >
+                <option value="">{t('no_task')}</option>
+                {tasks.filter(t => !t.completed).map((task) => (
+                  <option key={task.id} value={task.id}>
+                    {task.title}
+                  </option>
+                ))}
+              </select>
+            </div>
+          )}
+
           {/* Timer Controls */}
           <div className="flex justify-center gap-4">
             {!timerState.is_running ? (
-              <Button onClick={handleStart} size="lg" className="w-32">
-                {/*<PlayIcon className="mr-2 h-6 w-6" />*/}
-                {t('start')}
-              </Button>
+              <>
+                <Button onClick={() => handleStart()} size="lg" className="w-32">
+                  {/*<PlayIcon className="mr-2 h-6 w-6" />*/}
+                  {t('start')}
+                </Button>
+                {timerState.phase === 'work' ? (
+                  <Button onClick={() => handleStart(true)} size="lg" variant="outline" className="w-32">
+                    {t('stopwatch')}
+                  </Button>
+                ) : (
+                  <Button onClick={handleSkip} size="lg" variant="outline" className="w-32">
+                    {t('skip_phase')}
+                  </Button>
+                )}
+              </>
             ) : (
               <>
                 <Button onClick={handlePause} size="lg" variant="secondary" className="w-32">
@@ -280,11 +442,56 @@ export function PomodoroTimer() {
                   {/*<StopCircleIcon className="mr-2 h-6 w-6" />*/}
                   {t('stop')}
                 </Button>
+                {!isStopwatch && (
+                  <>
+                    <Button onClick={handleExtend} size="lg" variant="outline">
+                      {t('extend_phase')}
+                    </Button>
+                    <Button onClick={handleSkip} size="lg" variant="outline">
+                      {t('skip_phase')}
+                    </Button>
+                  </>
+                )}
               </>
             )}
           </div>
         </CardContent>
       </Card>
+
+      <Popup
+        title={t('idle_return_title')}
+        description={t('idle_return_message', { minutes: idleMinutes })}
+        isOpen={timerState.is_running && !!timerState.idle_returned_at}
+        footer={
+          <>
+            <Button variant="outline" onClick={() => handleResolveIdle('discard')}>
+              {t('idle_discard')}
+            </Button>
+            <Button variant="outline" onClick={() => handleResolveIdle('reassign')}>
+              {t('idle_reassign')}
+            </Button>
+            <Button onClick={() => handleResolveIdle('keep')}>
+              {t('idle_keep')}
+            </Button>
+          </>
+        }
+      >
+        <div className="space-y-2">
+          <Label>{t('select_task')}</Label>
+          <select
+            value={idleTask || ''}
+            onChange={(e) => setIdleTask(e.target.value ? parseInt(e.target.value) : null)}
+            className="w-full h-10 rounded-md border border-input bg-background px-3 py-2 text-sm ring-offset-background focus-visible:outline-none focus-visible:ring-2 focus-visible:ring-ring"
+          >
+            <option value="">{t('no_task')}</option>
+            {tasks.filter(t => !t.completed).map((task) => (
+              <option key={task.id} value={task.id}>
+                {task.title}
+              </option>
+            ))}
+          </select>
+        </div>
+      </Popup>
     </div>
   );
 }
@@ -27,7 +27,7 @@ export function TaskList() {
 
   const loadTasks = async () => {
     try {
-      const taskList = await GetTasks();
+      const taskList = await GetTasks({ tags: [], mode: '' });
       setTasks(taskList || []);
     } catch (err) {
       console.error('Failed to load tasks:', err);
@@ -39,7 +39,7 @@ export function TaskList() {
     if (!newTaskTitle.trim()) return;
 
     try {
-      await CreateTask(newTaskTitle, newTaskDescription);
+      await CreateTask(newTaskTitle, newTaskDescription, null);
       setNewTaskTitle('');
       setNewTaskDescription('');
       setShowAddForm(false);
@@ -61,7 +61,7 @@ export function TaskList() {
 
   const handleToggleComplete = async (task: any) => {
     try {
-      await UpdateTask(task.id, task.title, task.description, !task.completed);
+      await UpdateTask(task.id, task.title, task.description, !task.completed, task.project_id ?? null);
       loadTasks();
       toast({
         title: t('success'),
@@ -177,6 +177,28 @@ export function TaskList() {
                           {task.description}
                         </p>
                       )}
+                      {task.progress && (
+                        <p className="text-xs text-muted-foreground mt-1">
+                          {task.progress.completed}/{task.progress.total}
+                        </p>
+                      )}
+                      {task.children?.map((child: any) => (
+                        <div key={child.id} className="flex items-center space-x-2 mt-2">
+                          <button
+                            onClick={() => handleToggleComplete(child)}
+                            className={`h-4 w-4 rounded border-2 flex items-center justify-center transition-colors ${
+                              child.completed
+                                ? 'bg-primary border-primary text-primary-foreground'
+                                : 'border-muted-foreground hover:border-primary'
+                            }`}
+                          >
+                            {child.completed && <Check className="h-3 w-3" />}
+                          </button>
+                          <span className={`text-sm ${child.completed ? 'line-through' : ''}`}>
+                            {child.title}
+                          </span>
+                        </div>
+                      ))}
                     </div>
                   </div>
                   <Button
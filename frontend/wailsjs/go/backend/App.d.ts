@@ -1,40 +1,104 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
-import {menu} from '../models';
 import {backend} from '../models';
+import {menu} from '../models';
+
+export function AddAttachment(arg1:number,arg2:string):Promise<backend.Attachment>;
+
+export function AddSession(arg1:any,arg2:string,arg3:string,arg4:string):Promise<backend.PomodoroSession>;
+
+export function AddSessionTag(arg1:number,arg2:string):Promise<backend.Tag>;
+
+export function AddTaskDependency(arg1:number,arg2:number):Promise<void>;
+
+export function AddTaskTag(arg1:number,arg2:string):Promise<backend.Tag>;
+
+export function ArchiveProject(arg1:number):Promise<void>;
+
+export function AttachFiles(arg1:number):Promise<Array<backend.Attachment>>;
 
 export function BackupToDrive():Promise<void>;
 
+export function BulkCompleteTasks(arg1:Array<number>):Promise<Array<backend.BulkResult>>;
+
+export function BulkDeleteTasks(arg1:Array<number>):Promise<Array<backend.BulkResult>>;
+
+export function BulkMoveTasks(arg1:Array<number>,arg2:string):Promise<Array<backend.BulkResult>>;
+
+export function BulkReopenTasks(arg1:Array<number>):Promise<Array<backend.BulkResult>>;
+
+export function BulkRetagTasks(arg1:Array<number>,arg2:Array<string>,arg3:Array<string>):Promise<Array<backend.BulkResult>>;
+
+export function CleanupAttachments():Promise<number>;
+
 export function CompletePomodoro(arg1:number,arg2:any):Promise<void>;
 
 export function CreateAppMenu():Promise<menu.Menu>;
 
-export function CreateTask(arg1:string,arg2:string):Promise<backend.Task>;
+export function CreateProject(arg1:string,arg2:string):Promise<backend.Project>;
+
+export function CreateSubtask(arg1:number,arg2:string,arg3:string):Promise<backend.Task>;
+
+export function CreateTask(arg1:string,arg2:string,arg3:any):Promise<backend.Task>;
+
+export function CreateTemplate(arg1:backend.TaskTemplate):Promise<backend.TaskTemplate>;
+
+export function DeleteSession(arg1:number):Promise<void>;
 
 export function DeleteTask(arg1:number):Promise<void>;
 
+export function DeleteTemplate(arg1:number):Promise<void>;
+
+export function ExtendPomodoro(arg1:number):Promise<void>;
+
+export function GetActivity(arg1:string):Promise<Array<backend.TaskEvent>>;
+
 export function GetAppInfo():Promise<Record<string, any>>;
 
+export function GetAttachments(arg1:number):Promise<Array<backend.Attachment>>;
+
+export function GetBackupAttachments():Promise<boolean>;
+
+export function GetBoard(arg1:backend.TaskFilter):Promise<Array<backend.BoardColumn>>;
+
 export function GetCurrentUser():Promise<backend.User>;
 
 export function GetDailySummary(arg1:string):Promise<backend.DailySummary>;
 
 export function GetLanguage():Promise<string>;
 
+export function GetPomodoroSettings():Promise<backend.PomodoroSettings>;
+
+export function GetProjects(arg1:boolean):Promise<Array<backend.Project>>;
+
 export function GetReport(arg1:string,arg2:string):Promise<Record<string, any>>;
 
 export function GetServerHost():Promise<string>;
 
-export function GetSessions(arg1:string,arg2:string):Promise<Array<backend.PomodoroSession>>;
+export function GetSessions(arg1:string,arg2:string,arg3:Array<string>):Promise<Array<backend.PomodoroSession>>;
 
-export function GetTasks():Promise<Array<backend.Task>>;
+export function GetTags():Promise<Array<backend.Tag>>;
+
+export function GetTaskHistory(arg1:number):Promise<Array<backend.TaskEvent>>;
+
+export function GetTaskTransitions(arg1:number):Promise<Array<backend.TaskTransition>>;
+
+export function GetTasks(arg1:backend.TaskFilter):Promise<Array<backend.Task>>;
+
+export function GetTemplates():Promise<Array<backend.TaskTemplate>>;
 
 export function GetTimerState():Promise<backend.TimerState>;
 
+export function GetTrash():Promise<Array<backend.Task>>;
+
+export function GetTrashRetention():Promise<number>;
+
 export function GetUserDailyRetro(arg1:string):Promise<backend.DailyRetro>;
 
 export function GetWaterReminderSettings():Promise<backend.WaterReminderSettings>;
 
+export function GetWorkflowStates():Promise<Array<backend.WorkflowState>>;
+
 export function GoogleCallback(arg1:string):Promise<void>;
 
 export function GoogleLogin():Promise<string>;
@@ -43,46 +107,116 @@ export function HasGoogleCredentials():Promise<boolean>;
 
 export function HideWindow():Promise<void>;
 
+export function ImportTasks(arg1:string,arg2:string):Promise<backend.ImportPreview>;
+
+export function InstantiateTemplate(arg1:number,arg2:Array<string>):Promise<Array<backend.Task>>;
+
 export function IsGoogleAuthenticated():Promise<boolean>;
 
 export function LockScreen():Promise<void>;
 
+export function LogInterruption(arg1:string,arg2:string):Promise<void>;
+
 export function Login(arg1:string,arg2:string):Promise<backend.User>;
 
 export function Logout(arg1:string):Promise<void>;
 
+export function MergeSessions(arg1:number,arg2:number):Promise<backend.PomodoroSession>;
+
 export function MinimizeWindow():Promise<void>;
 
+export function MoveTask(arg1:number,arg2:string):Promise<void>;
+
+export function OpenAttachment(arg1:number):Promise<void>;
+
 export function PausePomodoro():Promise<void>;
 
 export function Ping():Promise<string>;
 
+export function PreviewImport(arg1:string,arg2:string):Promise<backend.ImportPreview>;
+
+export function PurgeTrash():Promise<number>;
+
 export function PushNotification(arg1:backend.Notification):Promise<void>;
 
 export function Register(arg1:string,arg2:string,arg3:string):Promise<void>;
 
+export function RemoveAttachment(arg1:number):Promise<void>;
+
+export function RemoveSessionTag(arg1:number,arg2:number):Promise<void>;
+
+export function RemoveTaskDependency(arg1:number,arg2:number):Promise<void>;
+
+export function RemoveTaskTag(arg1:number,arg2:number):Promise<void>;
+
+export function ReorderTasks(arg1:Array<number>):Promise<void>;
+
+export function ResetPomodoroCycle():Promise<void>;
+
+export function ResolveIdle(arg1:string,arg2:any):Promise<void>;
+
 export function RestoreFromDrive():Promise<void>;
 
 export function RestoreSession(arg1:string):Promise<backend.User>;
 
+export function RestoreTask(arg1:number):Promise<void>;
+
 export function ResumePomodoro():Promise<void>;
 
 export function SaveDailyRetro(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function SaveGoogleClientCredentials(arg1:string,arg2:string):Promise<void>;
 
+export function SavePomodoroSettings(arg1:backend.PomodoroSettings):Promise<void>;
+
 export function SaveServerHost(arg1:string):Promise<void>;
 
 export function SaveWaterReminderSettings(arg1:boolean,arg2:number,arg3:any):Promise<void>;
 
+export function Search(arg1:string):Promise<backend.SearchResults>;
+
+export function SetBackupAttachments(arg1:boolean):Promise<void>;
+
 export function SetLanguage(arg1:string):Promise<void>;
 
+export function SetSessionNotes(arg1:number,arg2:string):Promise<void>;
+
+export function SetTaskAutoComplete(arg1:number,arg2:boolean):Promise<void>;
+
+export function SetTaskDueDate(arg1:number,arg2:string):Promise<void>;
+
+export function SetTaskEstimate(arg1:number,arg2:number):Promise<void>;
+
+export function SetTaskParent(arg1:number,arg2:any):Promise<void>;
+
+export function SetTaskPriority(arg1:number,arg2:number):Promise<void>;
+
+export function SetTaskRecurrence(arg1:number,arg2:string):Promise<void>;
+
+export function SetTrashRetention(arg1:number):Promise<void>;
+
+export function SetWorkflowStates(arg1:Array<backend.WorkflowState>):Promise<Array<backend.WorkflowState>>;
+
 export function SetupSystemTray():Promise<void>;
 
 export function ShowWindow():Promise<void>;
 
-export function StartPomodoro(arg1:number,arg2:any):Promise<void>;
+export function SkipPhase():Promise<void>;
+
+export function SplitSession(arg1:number,arg2:string):Promise<Array<backend.PomodoroSession>>;
+
+export function StartPomodoro(arg1:number,arg2:any):Promise<Array<backend.Task>>;
+
+export function StartStopwatch(arg1:any):Promise<Array<backend.Task>>;
+
+export function StopPomodoro(arg1:string):Promise<void>;
+
+export function SwitchTask(arg1:any):Promise<Array<backend.Task>>;
+
+export function UpdateProject(arg1:number,arg2:string,arg3:string):Promise<void>;
+
+export function UpdateSession(arg1:number,arg2:any,arg3:string,arg4:string,arg5:string):Promise<backend.PomodoroSession>;
 
-export function StopPomodoro():Promise<void>;
+export function UpdateTask(arg1:number,arg2:string,arg3:string,arg4:boolean,arg5:any):Promise<void>;
 
-export function UpdateTask(arg1:number,arg2:string,arg3:string,arg4:boolean):Promise<void>;
+export function UpdateTemplate(arg1:backend.TaskTemplate):Promise<void>;
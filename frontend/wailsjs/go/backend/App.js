@@ -2,10 +2,62 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddAttachment(arg1, arg2) {
+  return window['go']['backend']['App']['AddAttachment'](arg1, arg2);
+}
+
+export function AddSession(arg1, arg2, arg3, arg4) {
+  return window['go']['backend']['App']['AddSession'](arg1, arg2, arg3, arg4);
+}
+
+export function AddSessionTag(arg1, arg2) {
+  return window['go']['backend']['App']['AddSessionTag'](arg1, arg2);
+}
+
+export function AddTaskDependency(arg1, arg2) {
+  return window['go']['backend']['App']['AddTaskDependency'](arg1, arg2);
+}
+
+export function AddTaskTag(arg1, arg2) {
+  return window['go']['backend']['App']['AddTaskTag'](arg1, arg2);
+}
+
+export function ArchiveProject(arg1) {
+  return window['go']['backend']['App']['ArchiveProject'](arg1);
+}
+
+export function AttachFiles(arg1) {
+  return window['go']['backend']['App']['AttachFiles'](arg1);
+}
+
 export function BackupToDrive() {
   return window['go']['backend']['App']['BackupToDrive']();
 }
 
+export function BulkCompleteTasks(arg1) {
+  return window['go']['backend']['App']['BulkCompleteTasks'](arg1);
+}
+
+export function BulkDeleteTasks(arg1) {
+  return window['go']['backend']['App']['BulkDeleteTasks'](arg1);
+}
+
+export function BulkMoveTasks(arg1, arg2) {
+  return window['go']['backend']['App']['BulkMoveTasks'](arg1, arg2);
+}
+
+export function BulkReopenTasks(arg1) {
+  return window['go']['backend']['App']['BulkReopenTasks'](arg1);
+}
+
+export function BulkRetagTasks(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['BulkRetagTasks'](arg1, arg2, arg3);
+}
+
+export function CleanupAttachments() {
+  return window['go']['backend']['App']['CleanupAttachments']();
+}
+
 export function CompletePomodoro(arg1, arg2) {
   return window['go']['backend']['App']['CompletePomodoro'](arg1, arg2);
 }
@@ -14,18 +66,58 @@ export function CreateAppMenu() {
   return window['go']['backend']['App']['CreateAppMenu']();
 }
 
-export function CreateTask(arg1, arg2) {
-  return window['go']['backend']['App']['CreateTask'](arg1, arg2);
+export function CreateProject(arg1, arg2) {
+  return window['go']['backend']['App']['CreateProject'](arg1, arg2);
+}
+
+export function CreateSubtask(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['CreateSubtask'](arg1, arg2, arg3);
+}
+
+export function CreateTask(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['CreateTask'](arg1, arg2, arg3);
+}
+
+export function CreateTemplate(arg1) {
+  return window['go']['backend']['App']['CreateTemplate'](arg1);
+}
+
+export function DeleteSession(arg1) {
+  return window['go']['backend']['App']['DeleteSession'](arg1);
 }
 
 export function DeleteTask(arg1) {
   return window['go']['backend']['App']['DeleteTask'](arg1);
 }
 
+export function DeleteTemplate(arg1) {
+  return window['go']['backend']['App']['DeleteTemplate'](arg1);
+}
+
+export function ExtendPomodoro(arg1) {
+  return window['go']['backend']['App']['ExtendPomodoro'](arg1);
+}
+
+export function GetActivity(arg1) {
+  return window['go']['backend']['App']['GetActivity'](arg1);
+}
+
 export function GetAppInfo() {
   return window['go']['backend']['App']['GetAppInfo']();
 }
 
+export function GetAttachments(arg1) {
+  return window['go']['backend']['App']['GetAttachments'](arg1);
+}
+
+export function GetBackupAttachments() {
+  return window['go']['backend']['App']['GetBackupAttachments']();
+}
+
+export function GetBoard(arg1) {
+  return window['go']['backend']['App']['GetBoard'](arg1);
+}
+
 export function GetCurrentUser() {
   return window['go']['backend']['App']['GetCurrentUser']();
 }
@@ -38,6 +130,14 @@ export function GetLanguage() {
   return window['go']['backend']['App']['GetLanguage']();
 }
 
+export function GetPomodoroSettings() {
+  return window['go']['backend']['App']['GetPomodoroSettings']();
+}
+
+export function GetProjects(arg1) {
+  return window['go']['backend']['App']['GetProjects'](arg1);
+}
+
 export function GetReport(arg1, arg2) {
   return window['go']['backend']['App']['GetReport'](arg1, arg2);
 }
@@ -46,18 +146,42 @@ export function GetServerHost() {
   return window['go']['backend']['App']['GetServerHost']();
 }
 
-export function GetSessions(arg1, arg2) {
-  return window['go']['backend']['App']['GetSessions'](arg1, arg2);
+export function GetSessions(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['GetSessions'](arg1, arg2, arg3);
 }
 
-export function GetTasks() {
-  return window['go']['backend']['App']['GetTasks']();
+export function GetTags() {
+  return window['go']['backend']['App']['GetTags']();
+}
+
+export function GetTaskHistory(arg1) {
+  return window['go']['backend']['App']['GetTaskHistory'](arg1);
+}
+
+export function GetTaskTransitions(arg1) {
+  return window['go']['backend']['App']['GetTaskTransitions'](arg1);
+}
+
+export function GetTasks(arg1) {
+  return window['go']['backend']['App']['GetTasks'](arg1);
+}
+
+export function GetTemplates() {
+  return window['go']['backend']['App']['GetTemplates']();
 }
 
 export function GetTimerState() {
   return window['go']['backend']['App']['GetTimerState']();
 }
 
+export function GetTrash() {
+  return window['go']['backend']['App']['GetTrash']();
+}
+
+export function GetTrashRetention() {
+  return window['go']['backend']['App']['GetTrashRetention']();
+}
+
 export function GetUserDailyRetro(arg1) {
   return window['go']['backend']['App']['GetUserDailyRetro'](arg1);
 }
@@ -66,6 +190,10 @@ export function GetWaterReminderSettings() {
   return window['go']['backend']['App']['GetWaterReminderSettings']();
 }
 
+export function GetWorkflowStates() {
+  return window['go']['backend']['App']['GetWorkflowStates']();
+}
+
 export function GoogleCallback(arg1) {
   return window['go']['backend']['App']['GoogleCallback'](arg1);
 }
@@ -82,6 +210,14 @@ export function HideWindow() {
   return window['go']['backend']['App']['HideWindow']();
 }
 
+export function ImportTasks(arg1, arg2) {
+  return window['go']['backend']['App']['ImportTasks'](arg1, arg2);
+}
+
+export function InstantiateTemplate(arg1, arg2) {
+  return window['go']['backend']['App']['InstantiateTemplate'](arg1, arg2);
+}
+
 export function IsGoogleAuthenticated() {
   return window['go']['backend']['App']['IsGoogleAuthenticated']();
 }
@@ -90,6 +226,10 @@ export function LockScreen() {
   return window['go']['backend']['App']['LockScreen']();
 }
 
+export function LogInterruption(arg1, arg2) {
+  return window['go']['backend']['App']['LogInterruption'](arg1, arg2);
+}
+
 export function Login(arg1, arg2) {
   return window['go']['backend']['App']['Login'](arg1, arg2);
 }
@@ -98,10 +238,22 @@ export function Logout(arg1) {
   return window['go']['backend']['App']['Logout'](arg1);
 }
 
+export function MergeSessions(arg1, arg2) {
+  return window['go']['backend']['App']['MergeSessions'](arg1, arg2);
+}
+
 export function MinimizeWindow() {
   return window['go']['backend']['App']['MinimizeWindow']();
 }
 
+export function MoveTask(arg1, arg2) {
+  return window['go']['backend']['App']['MoveTask'](arg1, arg2);
+}
+
+export function OpenAttachment(arg1) {
+  return window['go']['backend']['App']['OpenAttachment'](arg1);
+}
+
 export function PausePomodoro() {
   return window['go']['backend']['App']['PausePomodoro']();
 }
@@ -110,6 +262,14 @@ export function Ping() {
   return window['go']['backend']['App']['Ping']();
 }
 
+export function PreviewImport(arg1, arg2) {
+  return window['go']['backend']['App']['PreviewImport'](arg1, arg2);
+}
+
+export function PurgeTrash() {
+  return window['go']['backend']['App']['PurgeTrash']();
+}
+
 export function PushNotification(arg1) {
   return window['go']['backend']['App']['PushNotification'](arg1);
 }
@@ -118,6 +278,34 @@ export function Register(arg1, arg2, arg3) {
   return window['go']['backend']['App']['Register'](arg1, arg2, arg3);
 }
 
+export function RemoveAttachment(arg1) {
+  return window['go']['backend']['App']['RemoveAttachment'](arg1);
+}
+
+export function RemoveSessionTag(arg1, arg2) {
+  return window['go']['backend']['App']['RemoveSessionTag'](arg1, arg2);
+}
+
+export function RemoveTaskDependency(arg1, arg2) {
+  return window['go']['backend']['App']['RemoveTaskDependency'](arg1, arg2);
+}
+
+export function RemoveTaskTag(arg1, arg2) {
+  return window['go']['backend']['App']['RemoveTaskTag'](arg1, arg2);
+}
+
+export function ReorderTasks(arg1) {
+  return window['go']['backend']['App']['ReorderTasks'](arg1);
+}
+
+export function ResetPomodoroCycle() {
+  return window['go']['backend']['App']['ResetPomodoroCycle']();
+}
+
+export function ResolveIdle(arg1, arg2) {
+  return window['go']['backend']['App']['ResolveIdle'](arg1, arg2);
+}
+
 export function RestoreFromDrive() {
   return window['go']['backend']['App']['RestoreFromDrive']();
 }
@@ -126,6 +314,10 @@ export function RestoreSession(arg1) {
   return window['go']['backend']['App']['RestoreSession'](arg1);
 }
 
+export function RestoreTask(arg1) {
+  return window['go']['backend']['App']['RestoreTask'](arg1);
+}
+
 export function ResumePomodoro() {
   return window['go']['backend']['App']['ResumePomodoro']();
 }
@@ -138,6 +330,10 @@ export function SaveGoogleClientCredentials(arg1, arg2) {
   return window['go']['backend']['App']['SaveGoogleClientCredentials'](arg1, arg2);
 }
 
+export function SavePomodoroSettings(arg1) {
+  return window['go']['backend']['App']['SavePomodoroSettings'](arg1);
+}
+
 export function SaveServerHost(arg1) {
   return window['go']['backend']['App']['SaveServerHost'](arg1);
 }
@@ -146,10 +342,54 @@ export function SaveWaterReminderSettings(arg1, arg2, arg3) {
   return window['go']['backend']['App']['SaveWaterReminderSettings'](arg1, arg2, arg3);
 }
 
+export function Search(arg1) {
+  return window['go']['backend']['App']['Search'](arg1);
+}
+
+export function SetBackupAttachments(arg1) {
+  return window['go']['backend']['App']['SetBackupAttachments'](arg1);
+}
+
 export function SetLanguage(arg1) {
   return window['go']['backend']['App']['SetLanguage'](arg1);
 }
 
+export function SetSessionNotes(arg1, arg2) {
+  return window['go']['backend']['App']['SetSessionNotes'](arg1, arg2);
+}
+
+export function SetTaskAutoComplete(arg1, arg2) {
+  return window['go']['backend']['App']['SetTaskAutoComplete'](arg1, arg2);
+}
+
+export function SetTaskDueDate(arg1, arg2) {
+  return window['go']['backend']['App']['SetTaskDueDate'](arg1, arg2);
+}
+
+export function SetTaskEstimate(arg1, arg2) {
+  return window['go']['backend']['App']['SetTaskEstimate'](arg1, arg2);
+}
+
+export function SetTaskParent(arg1, arg2) {
+  return window['go']['backend']['App']['SetTaskParent'](arg1, arg2);
+}
+
+export function SetTaskPriority(arg1, arg2) {
+  return window['go']['backend']['App']['SetTaskPriority'](arg1, arg2);
+}
+
+export function SetTaskRecurrence(arg1, arg2) {
+  return window['go']['backend']['App']['SetTaskRecurrence'](arg1, arg2);
+}
+
+export function SetTrashRetention(arg1) {
+  return window['go']['backend']['App']['SetTrashRetention'](arg1);
+}
+
+export function SetWorkflowStates(arg1) {
+  return window['go']['backend']['App']['SetWorkflowStates'](arg1);
+}
+
 export function SetupSystemTray() {
   return window['go']['backend']['App']['SetupSystemTray']();
 }
@@ -158,14 +398,42 @@ export function ShowWindow() {
   return window['go']['backend']['App']['ShowWindow']();
 }
 
+export function SkipPhase() {
+  return window['go']['backend']['App']['SkipPhase']();
+}
+
+export function SplitSession(arg1, arg2) {
+  return window['go']['backend']['App']['SplitSession'](arg1, arg2);
+}
+
 export function StartPomodoro(arg1, arg2) {
   return window['go']['backend']['App']['StartPomodoro'](arg1, arg2);
 }
 
-export function StopPomodoro() {
-  return window['go']['backend']['App']['StopPomodoro']();
+export function StartStopwatch(arg1) {
+  return window['go']['backend']['App']['StartStopwatch'](arg1);
+}
+
+export function StopPomodoro(arg1) {
+  return window['go']['backend']['App']['StopPomodoro'](arg1);
+}
+
+export function SwitchTask(arg1) {
+  return window['go']['backend']['App']['SwitchTask'](arg1);
+}
+
+export function UpdateProject(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['UpdateProject'](arg1, arg2, arg3);
+}
+
+export function UpdateSession(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['backend']['App']['UpdateSession'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function UpdateTask(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['backend']['App']['UpdateTask'](arg1, arg2, arg3, arg4, arg5);
 }
 
-export function UpdateTask(arg1, arg2, arg3, arg4) {
-  return window['go']['backend']['App']['UpdateTask'](arg1, arg2, arg3, arg4);
+export function UpdateTemplate(arg1) {
+  return window['go']['backend']['App']['UpdateTemplate'](arg1);
 }
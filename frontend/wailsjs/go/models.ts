@@ -1,29 +1,81 @@
 export namespace backend {
 	
-	export class DailyRetro {
+	export class Attachment {
 	    id: number;
 	    user_id: number;
-	    date: string;
-	    retro_notes: string;
-	    plan_notes: string;
+	    task_id: number;
+	    name: string;
+	    hash: string;
+	    size: number;
+	    mime_type: string;
 	    // Go type: time
 	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Attachment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.user_id = source["user_id"];
+	        this.task_id = source["task_id"];
+	        this.name = source["name"];
+	        this.hash = source["hash"];
+	        this.size = source["size"];
+	        this.mime_type = source["mime_type"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskProgress {
+	    completed: number;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.completed = source["completed"];
+	        this.total = source["total"];
+	    }
+	}
+	export class Tag {
+	    id: number;
+	    user_id: number;
+	    name: string;
 	    // Go type: time
-	    updated_at: any;
+	    created_at: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new DailyRetro(source);
+	        return new Tag(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
 	        this.user_id = source["user_id"];
-	        this.date = source["date"];
-	        this.retro_notes = source["retro_notes"];
-	        this.plan_notes = source["plan_notes"];
+	        this.name = source["name"];
 	        this.created_at = this.convertValues(source["created_at"], null);
-	        this.updated_at = this.convertValues(source["updated_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -54,6 +106,23 @@ export namespace backend {
 	    created_at: any;
 	    // Go type: time
 	    completed_at?: any;
+	    project_id?: number;
+	    tags: Tag[];
+	    parent_id?: number;
+	    auto_complete: boolean;
+	    children?: Task[];
+	    progress?: TaskProgress;
+	    estimated_pomodoros: number;
+	    due_date?: string;
+	    priority: number;
+	    sort_rank: number;
+	    recurrence?: string;
+	    occurrence: number;
+	    // Go type: time
+	    deleted_at?: any;
+	    state: string;
+	    blocked_by?: number[];
+	    blocked: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Task(source);
@@ -68,6 +137,134 @@ export namespace backend {
 	        this.completed = source["completed"];
 	        this.created_at = this.convertValues(source["created_at"], null);
 	        this.completed_at = this.convertValues(source["completed_at"], null);
+	        this.project_id = source["project_id"];
+	        this.tags = this.convertValues(source["tags"], Tag);
+	        this.parent_id = source["parent_id"];
+	        this.auto_complete = source["auto_complete"];
+	        this.children = this.convertValues(source["children"], Task);
+	        this.progress = this.convertValues(source["progress"], TaskProgress);
+	        this.estimated_pomodoros = source["estimated_pomodoros"];
+	        this.due_date = source["due_date"];
+	        this.priority = source["priority"];
+	        this.sort_rank = source["sort_rank"];
+	        this.recurrence = source["recurrence"];
+	        this.occurrence = source["occurrence"];
+	        this.deleted_at = this.convertValues(source["deleted_at"], null);
+	        this.state = source["state"];
+	        this.blocked_by = source["blocked_by"];
+	        this.blocked = source["blocked"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorkflowState {
+	    id: number;
+	    user_id: number;
+	    name: string;
+	    position: number;
+	    terminal: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.user_id = source["user_id"];
+	        this.name = source["name"];
+	        this.position = source["position"];
+	        this.terminal = source["terminal"];
+	    }
+	}
+	export class BoardColumn {
+	    state: WorkflowState;
+	    tasks: Task[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BoardColumn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.state = this.convertValues(source["state"], WorkflowState);
+	        this.tasks = this.convertValues(source["tasks"], Task);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BulkResult {
+	    task_id: number;
+	    success: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BulkResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_id = source["task_id"];
+	        this.success = source["success"];
+	        this.error = source["error"];
+	    }
+	}
+	export class DailyRetro {
+	    id: number;
+	    user_id: number;
+	    date: string;
+	    retro_notes: string;
+	    plan_notes: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new DailyRetro(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.user_id = source["user_id"];
+	        this.date = source["date"];
+	        this.retro_notes = source["retro_notes"];
+	        this.plan_notes = source["plan_notes"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -124,6 +321,112 @@ export namespace backend {
 		    return a;
 		}
 	}
+	export class ImportedTask {
+	    title: string;
+	    description?: string;
+	    completed: boolean;
+	    due_date?: string;
+	    priority: number;
+	    tags: string[];
+	    parent: number;
+	    duplicate: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportedTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.description = source["description"];
+	        this.completed = source["completed"];
+	        this.due_date = source["due_date"];
+	        this.priority = source["priority"];
+	        this.tags = source["tags"];
+	        this.parent = source["parent"];
+	        this.duplicate = source["duplicate"];
+	    }
+	}
+	export class ImportPreview {
+	    format: string;
+	    tasks: ImportedTask[];
+	    created: number;
+	    skipped: number;
+	    dry_run: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.tasks = this.convertValues(source["tasks"], ImportedTask);
+	        this.created = source["created"];
+	        this.skipped = source["skipped"];
+	        this.dry_run = source["dry_run"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Interruption {
+	    id: number;
+	    user_id: number;
+	    session_id: number;
+	    kind: string;
+	    note?: string;
+	    // Go type: time
+	    occurred_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Interruption(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.user_id = source["user_id"];
+	        this.session_id = source["session_id"];
+	        this.kind = source["kind"];
+	        this.note = source["note"];
+	        this.occurred_at = this.convertValues(source["occurred_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class Notification {
 	    AppID: string;
 	    Title: string;
@@ -140,6 +443,40 @@ export namespace backend {
 	        this.Message = source["Message"];
 	    }
 	}
+	export class PauseSegment {
+	    // Go type: time
+	    paused_at: any;
+	    // Go type: time
+	    resumed_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new PauseSegment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.paused_at = this.convertValues(source["paused_at"], null);
+	        this.resumed_at = this.convertValues(source["resumed_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class PomodoroSession {
 	    id: number;
 	    user_id: number;
@@ -149,6 +486,14 @@ export namespace backend {
 	    started_at: any;
 	    // Go type: time
 	    completed_at: any;
+	    tags: Tag[];
+	    notes?: string;
+	    mode: string;
+	    outcome: string;
+	    reason?: string;
+	    paused_seconds: number;
+	    pauses: PauseSegment[];
+	    interruptions: Interruption[];
 	
 	    static createFrom(source: any = {}) {
 	        return new PomodoroSession(source);
@@ -162,6 +507,14 @@ export namespace backend {
 	        this.duration = source["duration"];
 	        this.started_at = this.convertValues(source["started_at"], null);
 	        this.completed_at = this.convertValues(source["completed_at"], null);
+	        this.tags = this.convertValues(source["tags"], Tag);
+	        this.notes = source["notes"];
+	        this.mode = source["mode"];
+	        this.outcome = source["outcome"];
+	        this.reason = source["reason"];
+	        this.paused_seconds = source["paused_seconds"];
+	        this.pauses = this.convertValues(source["pauses"], PauseSegment);
+	        this.interruptions = this.convertValues(source["interruptions"], Interruption);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -182,7 +535,315 @@ export namespace backend {
 		    return a;
 		}
 	}
+	export class PomodoroSettings {
+	    work_mins: number;
+	    short_break_mins: number;
+	    long_break_mins: number;
+	    long_break_every: number;
+	    auto_start_breaks: boolean;
+	    auto_start_work: boolean;
+	    min_record_mins: number;
+	    idle_pause_mins: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PomodoroSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.work_mins = source["work_mins"];
+	        this.short_break_mins = source["short_break_mins"];
+	        this.long_break_mins = source["long_break_mins"];
+	        this.long_break_every = source["long_break_every"];
+	        this.auto_start_breaks = source["auto_start_breaks"];
+	        this.auto_start_work = source["auto_start_work"];
+	        this.min_record_mins = source["min_record_mins"];
+	        this.idle_pause_mins = source["idle_pause_mins"];
+	    }
+	}
+	export class Project {
+	    id: number;
+	    user_id: number;
+	    name: string;
+	    color: string;
+	    archived: boolean;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    archived_at?: any;
 	
+	    static createFrom(source: any = {}) {
+	        return new Project(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.user_id = source["user_id"];
+	        this.name = source["name"];
+	        this.color = source["color"];
+	        this.archived = source["archived"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.archived_at = this.convertValues(source["archived_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchResult {
+	    type: string;
+	    id: number;
+	    title: string;
+	    snippet: string;
+	    date?: string;
+	    rank: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.snippet = source["snippet"];
+	        this.date = source["date"];
+	        this.rank = source["rank"];
+	    }
+	}
+	export class SearchResults {
+	    tasks: SearchResult[];
+	    retros: SearchResult[];
+	    sessions: SearchResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResults(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tasks = this.convertValues(source["tasks"], SearchResult);
+	        this.retros = this.convertValues(source["retros"], SearchResult);
+	        this.sessions = this.convertValues(source["sessions"], SearchResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class TaskEvent {
+	    id: number;
+	    user_id: number;
+	    task_id: number;
+	    task_title?: string;
+	    action: string;
+	    before?: Record<string, any>;
+	    after?: Record<string, any>;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.user_id = source["user_id"];
+	        this.task_id = source["task_id"];
+	        this.task_title = source["task_title"];
+	        this.action = source["action"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskFilter {
+	    tags: string[];
+	    mode: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tags = source["tags"];
+	        this.mode = source["mode"];
+	    }
+	}
+	
+	export class TaskTemplate {
+	    id: number;
+	    user_id: number;
+	    name: string;
+	    title_pattern: string;
+	    description: string;
+	    estimated_pomodoros: number;
+	    tags: string[];
+	    checklist: string[];
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.user_id = source["user_id"];
+	        this.name = source["name"];
+	        this.title_pattern = source["title_pattern"];
+	        this.description = source["description"];
+	        this.estimated_pomodoros = source["estimated_pomodoros"];
+	        this.tags = source["tags"];
+	        this.checklist = source["checklist"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskTransition {
+	    id: number;
+	    user_id: number;
+	    task_id: number;
+	    from_state: string;
+	    to_state: string;
+	    // Go type: time
+	    moved_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskTransition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.user_id = source["user_id"];
+	        this.task_id = source["task_id"];
+	        this.from_state = source["from_state"];
+	        this.to_state = source["to_state"];
+	        this.moved_at = this.convertValues(source["moved_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskSwitch {
+	    task_id?: number;
+	    // Go type: time
+	    switched_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskSwitch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_id = source["task_id"];
+	        this.switched_at = this.convertValues(source["switched_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class TimerState {
 	    is_running: boolean;
 	    is_paused: boolean;
@@ -191,6 +852,20 @@ export namespace backend {
 	    task_id?: number;
 	    // Go type: time
 	    started_at?: any;
+	    mode: string;
+	    elapsed: number;
+	    // Go type: time
+	    paused_at?: any;
+	    paused_seconds: number;
+	    pauses?: PauseSegment[];
+	    interruptions?: Interruption[];
+	    task_switches?: TaskSwitch[];
+	    idle_paused: boolean;
+	    // Go type: time
+	    idle_returned_at?: any;
+	    phase: string;
+	    cycle_count: number;
+	    long_break_every: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new TimerState(source);
@@ -204,6 +879,18 @@ export namespace backend {
 	        this.time_remaining = source["time_remaining"];
 	        this.task_id = source["task_id"];
 	        this.started_at = this.convertValues(source["started_at"], null);
+	        this.mode = source["mode"];
+	        this.elapsed = source["elapsed"];
+	        this.paused_at = this.convertValues(source["paused_at"], null);
+	        this.paused_seconds = source["paused_seconds"];
+	        this.pauses = this.convertValues(source["pauses"], PauseSegment);
+	        this.interruptions = this.convertValues(source["interruptions"], Interruption);
+	        this.task_switches = this.convertValues(source["task_switches"], TaskSwitch);
+	        this.idle_paused = source["idle_paused"];
+	        this.idle_returned_at = this.convertValues(source["idle_returned_at"], null);
+	        this.phase = source["phase"];
+	        this.cycle_count = source["cycle_count"];
+	        this.long_break_every = source["long_break_every"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {